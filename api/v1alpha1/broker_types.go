@@ -30,11 +30,20 @@ type BrokerSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// List of the components to be installed - any of [service-discovery, connectivity].
+	// List of the components to be installed - any of [service-discovery, connectivity]. All the components are installed
+	// if none are specified.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Components"
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	Components []string `json:"components,omitempty"`
 
+	// The broker API URL advertised to joining clusters. If not specified, the URL the operator uses to access
+	// the broker cluster is advertised.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker API Server"
+	//nolint:lll // Markers can't be wrapped
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	BrokerK8sApiServer string `json:"brokerK8sApiServer,omitempty"`
+
 	// List of domains to use for multi-cluster service discovery.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Default Custom Domains"
 	//nolint:lll // Markers can't be wrapped
@@ -70,12 +79,104 @@ type BrokerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	// +optional
 	ClustersetIPEnabled bool `json:"clustersetIPEnabled,omitempty"`
+
+	// The time after which a member cluster that has stopped renewing its heartbeat on the broker is considered stale.
+	// Stale clusters aren't detected if not specified. Clusters which have never sent a heartbeat are never considered stale.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Stale Cluster Timeout"
+	//nolint:lll // Markers can't be wrapped
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	StaleClusterTimeout *metav1.Duration `json:"staleClusterTimeout,omitempty"`
+
+	// The time a stale cluster remains marked as such before its broker resources are removed and its CIDRs are released.
+	// Defaults to the stale cluster timeout.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Stale Cluster Grace Period"
+	//nolint:lll // Markers can't be wrapped
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	StaleClusterGracePeriod *metav1.Duration `json:"staleClusterGracePeriod,omitempty"`
 }
 
+// Components which may be listed in BrokerSpec.Components.
+const (
+	ComponentServiceDiscovery = "service-discovery"
+	ComponentConnectivity     = "connectivity"
+)
+
 // BrokerStatus defines the observed state of Broker.
 type BrokerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Member clusters which have stopped renewing their heartbeat and whose broker resources are pending removal.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Stale Clusters"
+	StaleClusters []StaleCluster `json:"staleClusters,omitempty"`
+
+	// The utilization of the globalnet supernet, if globalnet is enabled.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Globalnet CIDR Pool"
+	// +optional
+	GlobalnetCIDRPool *CIDRPoolStatus `json:"globalnetCIDRPool,omitempty"`
+
+	// The utilization of the clustersetIP supernet, if clustersetIP is enabled.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="ClustersetIP CIDR Pool"
+	// +optional
+	ClustersetIPCIDRPool *CIDRPoolStatus `json:"clustersetIPCIDRPool,omitempty"`
+
+	// The overlapping cluster and service CIDRs of member clusters which don't use globalnet.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="CIDR Conflicts"
+	// +optional
+	CIDRConflicts []CIDRConflict `json:"cidrConflicts,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CIDRConflict describes a cluster or service CIDR of a member cluster which overlaps one of another member cluster.
+type CIDRConflict struct {
+	ClusterID      string `json:"clusterID"`
+	CIDR           string `json:"cidr"`
+	OtherClusterID string `json:"otherClusterID"`
+	OtherCIDR      string `json:"otherCIDR"`
+}
+
+// CIDRsOverlapCondition is set on Brokers, and on the Submariner resources of member clusters, to indicate whether
+// the cluster and service CIDRs of member clusters which don't use globalnet overlap.
+const CIDRsOverlapCondition = "CIDRsOverlap"
+
+// CIDRPoolStatus describes the utilization of a supernet from which CIDRs are allocated to member clusters. Address
+// counts are decimal strings since IPv6 supernets can hold more than 2^64 addresses.
+type CIDRPoolStatus struct {
+	// The supernet.
+	CIDR string `json:"cidr"`
+
+	// The default number of addresses allocated to each cluster.
+	AllocationSize string `json:"allocationSize,omitempty"`
+
+	// The number of addresses in the supernet.
+	TotalAddresses string `json:"totalAddresses"`
+
+	// The number of addresses allocated to clusters.
+	UsedAddresses string `json:"usedAddresses"`
+
+	// The number of further allocations of the default size which fit in the supernet.
+	AvailableAllocations string `json:"availableAllocations"`
+
+	// The percentage of unallocated addresses which can't be used by allocations of the default size.
+	FragmentationPercent int32 `json:"fragmentationPercent"`
+
+	// The CIDR the next cluster would be allocated with the default size, if any.
+	// +optional
+	NextAllocation string `json:"nextAllocation,omitempty"`
+}
+
+type StaleCluster struct {
+	ClusterID string `json:"clusterID"`
+
+	// The last time the cluster renewed its heartbeat.
+	LastHeartbeat metav1.Time `json:"lastHeartbeat,omitempty"`
+
+	// The time at which the cluster was found to be stale.
+	StaleSince metav1.Time `json:"staleSince,omitempty"`
 }
 
 //+kubebuilder:object:root=true
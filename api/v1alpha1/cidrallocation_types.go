@@ -0,0 +1,79 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// CIDRAllocationSpec defines the CIDRs allocated to a cluster from one of the broker's pools.
+type CIDRAllocationSpec struct {
+	// The ID of the cluster the CIDRs are allocated to.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster ID"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClusterID string `json:"clusterID"`
+
+	// The pool the CIDRs are allocated from - either globalnet or clustersetip.
+	// +kubebuilder:validation:Enum=globalnet;clustersetip
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pool"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Pool string `json:"pool"`
+
+	// The CIDRs allocated to the cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CIDRs"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CIDRs []string `json:"cidrs"`
+}
+
+// Pools which may be specified in CIDRAllocationSpec.Pool.
+const (
+	CIDRAllocationPoolGlobalnet    = "globalnet"
+	CIDRAllocationPoolClustersetIP = "clustersetip"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=cidrallocations,scope=Namespaced
+//+kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterID`
+//+kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.spec.pool`
+//+kubebuilder:printcolumn:name="CIDRs",type=string,JSONPath=`.spec.cidrs`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// CIDRAllocation records the CIDRs allocated to a cluster from one of the broker's pools.
+// +operator-sdk:csv:customresourcedefinitions:displayName="CIDR Allocation",resources={{Deployment,v1,submariner-operator}}
+type CIDRAllocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CIDRAllocationSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CIDRAllocationList contains a list of CIDRAllocation.
+type CIDRAllocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CIDRAllocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CIDRAllocation{}, &CIDRAllocationList{})
+}
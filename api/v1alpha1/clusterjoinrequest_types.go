@@ -0,0 +1,136 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ClusterJoinRequestSpec defines the desired state of ClusterJoinRequest.
+type ClusterJoinRequestSpec struct {
+	// The ID of the cluster joining the cluster set. It can't be changed, since the cluster is granted access to its own
+	// requests.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterID is immutable"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster ID"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClusterID string `json:"clusterID"`
+
+	// The global CIDR to assign to the cluster, if Globalnet is enabled. If not specified, one is allocated from the Broker's
+	// Globalnet CIDR range.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Global CIDR"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	// +optional
+	GlobalCIDR string `json:"globalCIDR,omitempty"`
+
+	// The number of global IPs in each of the cluster's global CIDR blocks, overriding the Broker's default cluster size.
+	// It can't be combined with GlobalCIDR.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Globalnet Cluster Size"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	// +optional
+	GlobalnetClusterSize uint `json:"globalnetClusterSize,omitempty"`
+
+	// The number of global CIDR blocks to allocate to the cluster, 1 by default. It can be increased once the cluster has
+	// joined to give it more global IPs; blocks which are already allocated are never taken back.
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Global CIDR Blocks"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	// +optional
+	GlobalCIDRBlocks uint `json:"globalCIDRBlocks,omitempty"`
+
+	// The ClustersetIP CIDR to assign to the cluster. If not specified, one is allocated from the Broker's ClustersetIP
+	// CIDR range.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ClustersetIP CIDR"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	// +optional
+	ClustersetIPCIDR string `json:"clustersetIPCIDR,omitempty"`
+}
+
+// ClusterJoinRequestStatus defines the observed state of ClusterJoinRequest.
+type ClusterJoinRequestStatus struct {
+	// The broker ServiceAccount created for the cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Service Account"
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// The global CIDR allocated to the cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Global CIDR"
+	GlobalCIDR string `json:"globalCIDR,omitempty"`
+
+	// The further global CIDR blocks allocated to the cluster, as requested by GlobalCIDRBlocks.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Additional Global CIDRs"
+	AdditionalGlobalCIDRs []string `json:"additionalGlobalCIDRs,omitempty"`
+
+	// The ClustersetIP CIDR allocated to the cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="ClustersetIP CIDR"
+	ClustersetIPCIDR string `json:"clustersetIPCIDR,omitempty"`
+
+	// The Secret holding the settings the cluster needs to join, once they are all available.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Join Bundle Secret"
+	JoinBundleSecret string `json:"joinBundleSecret,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ClusterJoinRequestReady indicates whether the join bundle for the cluster is complete.
+const ClusterJoinRequestReady = "Ready"
+
+// Keys of the join bundle Secret. They are named after the SubmarinerSpec fields they are intended for.
+const (
+	JoinBundleBrokerK8sApiServer       = "brokerK8sApiServer"
+	JoinBundleBrokerK8sApiServerToken  = "brokerK8sApiServerToken"
+	JoinBundleBrokerK8sCA              = "brokerK8sCA"
+	JoinBundleBrokerK8sRemoteNamespace = "brokerK8sRemoteNamespace"
+	JoinBundleClusterID                = "clusterID"
+	JoinBundleGlobalCIDR               = "globalCIDR"
+	JoinBundleAdditionalGlobalCIDRs    = "additionalGlobalCIDRs" // Comma-separated
+	JoinBundleClustersetIPCIDR         = "clustersetIPCIDR"
+	JoinBundleClustersetIPEnabled      = "clustersetIPEnabled"
+	JoinBundleServiceDiscoveryEnabled  = "serviceDiscoveryEnabled"
+	JoinBundleCustomDomains            = "customDomains"
+	JoinBundleCeIPSecPSK               = "ceIPSecPSK"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clusterjoinrequests,scope=Namespaced
+
+// ClusterJoinRequest is the Schema for the clusterjoinrequests API.
+// +operator-sdk:csv:customresourcedefinitions:displayName="Cluster Join Request",resources={{Deployment,v1,submariner-operator}}
+type ClusterJoinRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterJoinRequestSpec   `json:"spec,omitempty"`
+	Status ClusterJoinRequestStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterJoinRequestList contains a list of ClusterJoinRequest.
+type ClusterJoinRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterJoinRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterJoinRequest{}, &ClusterJoinRequestList{})
+}
@@ -55,6 +55,13 @@ type SubmarinerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:password"}
 	BrokerK8sCA string `json:"brokerK8sCA,omitempty"`
 
+	// Additional broker API endpoints, in order of preference, used as fallbacks if BrokerK8sApiServer becomes
+	// unavailable. The first available endpoint is used, starting with BrokerK8sApiServer.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker API Endpoints"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	BrokerK8sEndpoints []BrokerK8sEndpoint `json:"brokerK8sEndpoints,omitempty"`
+
 	BrokerK8sSecret string `json:"brokerK8sSecret,omitempty"`
 
 	// The Broker namespace.
@@ -80,6 +87,13 @@ type SubmarinerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ClusterCIDR string `json:"clusterCIDR"`
 
+	// All the cluster CIDRs, for clusters with several pod networks such as dual-stack clusters. When specified, they
+	// take precedence over ClusterCIDR.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster CIDRs"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	// +optional
+	ClusterCIDRs []string `json:"clusterCIDRs,omitempty"`
+
 	// The cluster ID used to identify the tunnels.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster ID"
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
@@ -98,13 +112,37 @@ type SubmarinerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ServiceCIDR string `json:"serviceCIDR"`
 
+	// All the service CIDRs, for clusters with several service networks such as dual-stack clusters. When specified,
+	// they take precedence over ServiceCIDR.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Service CIDRs"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	// +optional
+	ServiceCIDRs []string `json:"serviceCIDRs,omitempty"`
+
 	// The Global CIDR super-net range for allocating GlobalCIDRs to each cluster.
+	// If empty and globalnet is enabled on the broker, a CIDR is allocated to the cluster from the broker's pool and recorded in the status.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Global CIDR"
 	//nolint:lll // Markers can't be wrapped
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	GlobalCIDR string `json:"globalCIDR,omitempty"`
 
+	// Further global CIDR blocks allocated to the cluster when it needs more global IPs than GlobalCIDR provides.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Global CIDRs"
+	//nolint:lll // Markers can't be wrapped
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	AdditionalGlobalCIDRs []string `json:"additionalGlobalCIDRs,omitempty"`
+
+	// The number of global IPs to request in the cluster's global CIDR when it is allocated from the broker, overriding the
+	// broker's default cluster size. The broker rounds it up to a power of two. It is ignored if GlobalCIDR is specified.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Globalnet Cluster Size"
+	//nolint:lll // Markers can't be wrapped
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	GlobalnetClusterSize uint `json:"globalnetClusterSize,omitempty"`
+
 	// ClustersetIP CIDR for allocating ClustersetIPs to exported services.
+	// If empty and ClustersetIPEnabled is set, a CIDR is allocated to the cluster from the broker's pool and recorded in the status.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ClustersetIP CIDR"
 	//nolint:lll // Markers can't be wrapped
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -169,6 +207,22 @@ type SubmarinerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	LoadBalancerEnabled bool `json:"loadBalancerEnabled,omitempty"`
 
+	// The interval in seconds at which the cluster network is re-discovered, to notice changes such as CNI migrations or
+	// added service CIDRs. Intervals shorter than a minute are rounded up to a minute. Zero disables periodic
+	// re-discovery; changes to the network plugin's configuration still trigger it.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Network Discovery Interval"
+	//nolint:lll // Markers can't be wrapped
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	NetworkDiscoveryIntervalSeconds uint64 `json:"networkDiscoveryIntervalSeconds,omitempty"`
+
+	// Apply changes found by network re-discovery, rolling the route agent and other components with the new network
+	// details. Otherwise, they're only reported by the NetworkDrift condition and events.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Apply Network Changes"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	// +optional
+	ApplyNetworkChanges bool `json:"applyNetworkChanges,omitempty"`
+
 	// Enable support for Service Discovery (Lighthouse).
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Service Discovery"
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
@@ -235,22 +289,42 @@ type SubmarinerStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ClusterID string `json:"clusterID"`
 
-	// The current service CIDR.
+	// The current service CIDR; the first of ServiceCIDRs.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Service CIDR"
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ServiceCIDR string `json:"serviceCIDR,omitempty"`
 
-	// The current cluster CIDR.
+	// All the current service CIDRs.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Service CIDRs"
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ServiceCIDRs []string `json:"serviceCIDRs,omitempty"`
+
+	// The current cluster CIDR; the first of ClusterCIDRs.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Cluster CIDR"
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ClusterCIDR string `json:"clusterCIDR,omitempty"`
 
-	// The current global CIDR.
+	// All the current cluster CIDRs.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Cluster CIDRs"
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClusterCIDRs []string `json:"clusterCIDRs,omitempty"`
+
+	// The current global CIDR, as specified or allocated from the broker.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Global CIDR"
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	GlobalCIDR string `json:"globalCIDR,omitempty"`
 
-	// The current clustersetIP CIDR.
+	// The current further global CIDR blocks, as specified or allocated from the broker.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Additional Global CIDRs"
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AdditionalGlobalCIDRs []string `json:"additionalGlobalCIDRs,omitempty"`
+
+	// The number of global IPs in the current global CIDR, as granted by the broker. It isn't reported while the allocation fails.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Globalnet Cluster Size"
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	GlobalnetClusterSize uint `json:"globalnetClusterSize,omitempty"`
+
+	// The current clustersetIP CIDR, as specified or allocated from the broker.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="ClustersetIP CIDR"
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ClustersetIPCIDR string `json:"clustersetIPCIDR,omitempty"`
@@ -260,6 +334,10 @@ type SubmarinerStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	NetworkPlugin string `json:"networkPlugin,omitempty"`
 
+	// Where the current network details came from.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Network Discovery"
+	NetworkDiscovery *NetworkDiscoveryStatus `json:"networkDiscovery,omitempty"`
+
 	// The status of the gateway DaemonSet.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Gateway DaemonSet Status"
 	GatewayDaemonSetStatus DaemonSetStatusWrapper `json:"gatewayDaemonSetStatus,omitempty"`
@@ -284,9 +362,20 @@ type SubmarinerStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Deployment Information"
 	DeploymentInfo DeploymentInfo `json:"deploymentInfo,omitempty"`
 
+	// The status of the syncer maintaining the local copy of the broker secret.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Broker Secret Syncer"
+	BrokerSecretSyncer *BrokerSecretSyncerStatus `json:"brokerSecretSyncer,omitempty"`
+
+	// The broker API server currently in use.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Active Broker API Server"
+	ActiveBrokerK8sApiServer string `json:"activeBrokerK8sApiServer,omitempty"`
+
 	// The image version in use by the various Submariner DaemonSets and Deployments.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Version"
 	Version string `json:"version,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -334,6 +423,56 @@ type DeploymentInfo struct {
 	CloudProvider         CloudProvider  `json:"cloudProvider,omitempty"`
 }
 
+// BrokerCIDRAllocationFailedCondition is set on Submariner resources when the cluster's global or ClustersetIP CIDR
+// can't be allocated from the broker.
+const BrokerCIDRAllocationFailedCondition = "BrokerCIDRAllocationFailed"
+
+// NetworkDriftCondition is set on Submariner resources when the re-discovered cluster network differs from the one the
+// components are running with.
+const NetworkDriftCondition = "NetworkDrift"
+
+type NetworkDiscoveryStatus struct {
+	// The source of the cluster CIDRs, such as KubeControllerManagerFlag or NodeSpec; Spec if they're configured.
+	ClusterCIDRsSource string `json:"clusterCIDRsSource,omitempty"`
+
+	// How reliable the discovered cluster CIDRs are: High, Medium or Low.
+	ClusterCIDRsConfidence string `json:"clusterCIDRsConfidence,omitempty"`
+
+	// The source of the service CIDRs, such as ServiceCIDR or KubeAPIServerFlag; Spec if they're configured.
+	ServiceCIDRsSource string `json:"serviceCIDRsSource,omitempty"`
+
+	// The resource the network plugin was detected from.
+	NetworkPluginEvidence string `json:"networkPluginEvidence,omitempty"`
+
+	// The network plugin's settings which affect the route agent, such as the encapsulation used by Calico's IPPools.
+	PluginSettings map[string]string `json:"pluginSettings,omitempty"`
+}
+
+type BrokerK8sEndpoint struct {
+	// The broker API URL.
+	ApiServer string `json:"apiServer"`
+
+	// The certificate authority for this endpoint. If not specified, BrokerK8sCA is used.
+	CA string `json:"ca,omitempty"`
+}
+
+type BrokerSecretSyncerStatus struct {
+	// The broker API server the syncer is connected to.
+	BrokerK8sApiServer string `json:"brokerK8sApiServer,omitempty"`
+
+	// The broker namespace the syncer watches.
+	BrokerK8sRemoteNamespace string `json:"brokerK8sRemoteNamespace,omitempty"`
+
+	// The time at which the syncer was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time of the last successful sync of the broker secret.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// The error returned by the last sync attempt, if it failed.
+	LastError string `json:"lastError,omitempty"`
+}
+
 type HealthCheckSpec struct {
 	// Enable the connection health check.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Connection Health Checks"
@@ -26,7 +26,8 @@ package v1alpha1
 import (
 	submariner_iov1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	appsv1 "k8s.io/api/apps/v1"
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -36,7 +37,7 @@ func (in *Broker) DeepCopyInto(out *Broker) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broker.
@@ -57,6 +58,21 @@ func (in *Broker) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerK8sEndpoint) DeepCopyInto(out *BrokerK8sEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerK8sEndpoint.
+func (in *BrokerK8sEndpoint) DeepCopy() *BrokerK8sEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerK8sEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerList) DeepCopyInto(out *BrokerList) {
 	*out = *in
@@ -89,6 +105,29 @@ func (in *BrokerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecretSyncerStatus) DeepCopyInto(out *BrokerSecretSyncerStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecretSyncerStatus.
+func (in *BrokerSecretSyncerStatus) DeepCopy() *BrokerSecretSyncerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecretSyncerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
 	*out = *in
@@ -102,6 +141,16 @@ func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StaleClusterTimeout != nil {
+		in, out := &in.StaleClusterTimeout, &out.StaleClusterTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.StaleClusterGracePeriod != nil {
+		in, out := &in.StaleClusterGracePeriod, &out.StaleClusterGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSpec.
@@ -117,6 +166,35 @@ func (in *BrokerSpec) DeepCopy() *BrokerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerStatus) DeepCopyInto(out *BrokerStatus) {
 	*out = *in
+	if in.StaleClusters != nil {
+		in, out := &in.StaleClusters, &out.StaleClusters
+		*out = make([]StaleCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GlobalnetCIDRPool != nil {
+		in, out := &in.GlobalnetCIDRPool, &out.GlobalnetCIDRPool
+		*out = new(CIDRPoolStatus)
+		**out = **in
+	}
+	if in.ClustersetIPCIDRPool != nil {
+		in, out := &in.ClustersetIPCIDRPool, &out.ClustersetIPCIDRPool
+		*out = new(CIDRPoolStatus)
+		**out = **in
+	}
+	if in.CIDRConflicts != nil {
+		in, out := &in.CIDRConflicts, &out.CIDRConflicts
+		*out = make([]CIDRConflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerStatus.
@@ -129,6 +207,215 @@ func (in *BrokerStatus) DeepCopy() *BrokerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRAllocation) DeepCopyInto(out *CIDRAllocation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRAllocation.
+func (in *CIDRAllocation) DeepCopy() *CIDRAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CIDRAllocation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRAllocationList) DeepCopyInto(out *CIDRAllocationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CIDRAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRAllocationList.
+func (in *CIDRAllocationList) DeepCopy() *CIDRAllocationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRAllocationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CIDRAllocationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRAllocationSpec) DeepCopyInto(out *CIDRAllocationSpec) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRAllocationSpec.
+func (in *CIDRAllocationSpec) DeepCopy() *CIDRAllocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRAllocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRConflict) DeepCopyInto(out *CIDRConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRConflict.
+func (in *CIDRConflict) DeepCopy() *CIDRConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRPoolStatus) DeepCopyInto(out *CIDRPoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRPoolStatus.
+func (in *CIDRPoolStatus) DeepCopy() *CIDRPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequest) DeepCopyInto(out *ClusterJoinRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequest.
+func (in *ClusterJoinRequest) DeepCopy() *ClusterJoinRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterJoinRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestList) DeepCopyInto(out *ClusterJoinRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterJoinRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestList.
+func (in *ClusterJoinRequestList) DeepCopy() *ClusterJoinRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterJoinRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestSpec) DeepCopyInto(out *ClusterJoinRequestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestSpec.
+func (in *ClusterJoinRequestSpec) DeepCopy() *ClusterJoinRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJoinRequestStatus) DeepCopyInto(out *ClusterJoinRequestStatus) {
+	*out = *in
+	if in.AdditionalGlobalCIDRs != nil {
+		in, out := &in.AdditionalGlobalCIDRs, &out.AdditionalGlobalCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJoinRequestStatus.
+func (in *ClusterJoinRequestStatus) DeepCopy() *ClusterJoinRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJoinRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoreDNSCustomConfig) DeepCopyInto(out *CoreDNSCustomConfig) {
 	*out = *in
@@ -154,10 +441,10 @@ func (in *DaemonSetStatusWrapper) DeepCopyInto(out *DaemonSetStatusWrapper) {
 	}
 	if in.NonReadyContainerStates != nil {
 		in, out := &in.NonReadyContainerStates, &out.NonReadyContainerStates
-		*out = new([]v1.ContainerState)
+		*out = new([]corev1.ContainerState)
 		if **in != nil {
 			in, out := *in, *out
-			*out = make([]v1.ContainerState, len(*in))
+			*out = make([]corev1.ContainerState, len(*in))
 			for i := range *in {
 				(*in)[i].DeepCopyInto(&(*out)[i])
 			}
@@ -210,7 +497,7 @@ func (in *LoadBalancerStatusWrapper) DeepCopyInto(out *LoadBalancerStatusWrapper
 	*out = *in
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(v1.LoadBalancerStatus)
+		*out = new(corev1.LoadBalancerStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -225,6 +512,28 @@ func (in *LoadBalancerStatusWrapper) DeepCopy() *LoadBalancerStatusWrapper {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDiscoveryStatus) DeepCopyInto(out *NetworkDiscoveryStatus) {
+	*out = *in
+	if in.PluginSettings != nil {
+		in, out := &in.PluginSettings, &out.PluginSettings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDiscoveryStatus.
+func (in *NetworkDiscoveryStatus) DeepCopy() *NetworkDiscoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDiscoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceDiscovery) DeepCopyInto(out *ServiceDiscovery) {
 	*out = *in
@@ -313,7 +622,7 @@ func (in *ServiceDiscoverySpec) DeepCopyInto(out *ServiceDiscoverySpec) {
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -346,6 +655,23 @@ func (in *ServiceDiscoveryStatus) DeepCopy() *ServiceDiscoveryStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleCluster) DeepCopyInto(out *StaleCluster) {
+	*out = *in
+	in.LastHeartbeat.DeepCopyInto(&out.LastHeartbeat)
+	in.StaleSince.DeepCopyInto(&out.StaleSince)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleCluster.
+func (in *StaleCluster) DeepCopy() *StaleCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Submariner) DeepCopyInto(out *Submariner) {
 	*out = *in
@@ -408,6 +734,26 @@ func (in *SubmarinerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubmarinerSpec) DeepCopyInto(out *SubmarinerSpec) {
 	*out = *in
+	if in.BrokerK8sEndpoints != nil {
+		in, out := &in.BrokerK8sEndpoints, &out.BrokerK8sEndpoints
+		*out = make([]BrokerK8sEndpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterCIDRs != nil {
+		in, out := &in.ClusterCIDRs, &out.ClusterCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceCIDRs != nil {
+		in, out := &in.ServiceCIDRs, &out.ServiceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalGlobalCIDRs != nil {
+		in, out := &in.AdditionalGlobalCIDRs, &out.AdditionalGlobalCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.CoreDNSCustomConfig != nil {
 		in, out := &in.CoreDNSCustomConfig, &out.CoreDNSCustomConfig
 		*out = new(CoreDNSCustomConfig)
@@ -439,7 +785,7 @@ func (in *SubmarinerSpec) DeepCopyInto(out *SubmarinerSpec) {
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -459,6 +805,26 @@ func (in *SubmarinerSpec) DeepCopy() *SubmarinerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubmarinerStatus) DeepCopyInto(out *SubmarinerStatus) {
 	*out = *in
+	if in.AdditionalGlobalCIDRs != nil {
+		in, out := &in.AdditionalGlobalCIDRs, &out.AdditionalGlobalCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceCIDRs != nil {
+		in, out := &in.ServiceCIDRs, &out.ServiceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterCIDRs != nil {
+		in, out := &in.ClusterCIDRs, &out.ClusterCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkDiscovery != nil {
+		in, out := &in.NetworkDiscovery, &out.NetworkDiscovery
+		*out = new(NetworkDiscoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	in.GatewayDaemonSetStatus.DeepCopyInto(&out.GatewayDaemonSetStatus)
 	in.RouteAgentDaemonSetStatus.DeepCopyInto(&out.RouteAgentDaemonSetStatus)
 	in.GlobalnetDaemonSetStatus.DeepCopyInto(&out.GlobalnetDaemonSetStatus)
@@ -475,6 +841,18 @@ func (in *SubmarinerStatus) DeepCopyInto(out *SubmarinerStatus) {
 		}
 	}
 	out.DeploymentInfo = in.DeploymentInfo
+	if in.BrokerSecretSyncer != nil {
+		in, out := &in.BrokerSecretSyncer, &out.BrokerSecretSyncer
+		*out = new(BrokerSecretSyncerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubmarinerStatus.
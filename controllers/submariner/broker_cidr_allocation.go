@@ -0,0 +1,243 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Brokers on which globalnet was found to be disabled are checked again after this interval, in case it's enabled later.
+	globalnetDisabledRecheckInterval = 10 * time.Minute
+
+	// The cluster's ClusterJoinRequest is checked at this interval until the broker has processed it.
+	brokerCIDRAllocationRetryInterval = 5 * time.Second
+)
+
+// allocateBrokerCIDRs determines the cluster's global CIDR and ClustersetIP CIDR, and records them in the status where
+// they are picked up by the rest of the reconcile. CIDRs specified in the spec are used as is; otherwise they are
+// requested from the broker through the cluster's ClusterJoinRequest, which the broker creates for every member
+// cluster, so that clusters installed without subctl get them too. Member clusters can't allocate CIDRs themselves. The
+// spec is left as specified by the user. The global CIDR is only allocated if globalnet is enabled on the broker; once
+// the broker, as reached through the active endpoint, is found not to have it enabled, it isn't checked again until
+// globalnetDisabledRecheckInterval has elapsed.
+// The requested cluster size is passed on to the broker, which determines the size it grants.
+// It returns true if the broker hasn't processed the request yet. Allocation failures, including those the broker
+// reports for the request, such as a cluster size it can't grant, are reported in the BrokerCIDRAllocationFailed
+// condition, and returned so that the allocation is retried.
+func (r *Reconciler) allocateBrokerCIDRs(ctx context.Context, instance *v1alpha1.Submariner) (bool, error) {
+	recordSpecifiedCIDRs(instance)
+
+	apiServer, _ := activeBrokerEndpoint(instance)
+	globalnetKey := globalnetBrokerKey(apiServer, instance.Spec.BrokerK8sRemoteNamespace)
+
+	needsGlobalCIDR := instance.Spec.GlobalCIDR == "" && !r.isGlobalnetDisabledOn(globalnetKey)
+	needsClustersetIPCIDR := instance.Spec.ClustersetIPEnabled && instance.Spec.ClustersetIPCIDR == ""
+
+	if instance.Spec.BrokerK8sRemoteNamespace == "" || (!needsGlobalCIDR && !needsClustersetIPCIDR) {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.BrokerCIDRAllocationFailedCondition)
+		return false, nil
+	}
+
+	brokerClient, err := r.getBrokerControllerClient(ctx, instance)
+	if err != nil {
+		return false, r.brokerCIDRAllocationFailed(instance, "BrokerUnreachable",
+			errors.Wrap(err, "unable to access the broker to allocate the cluster's CIDRs"))
+	}
+
+	request, err := findClusterJoinRequest(ctx, brokerClient, &instance.Spec)
+	if err != nil {
+		return false, r.brokerCIDRAllocationFailed(instance, "AllocationFailed", err)
+	}
+
+	// Brokers which predate broker-side allocation don't create ClusterJoinRequests; clusters joined by subctl have their
+	// CIDRs specified.
+	if request == nil {
+		if needsGlobalCIDR {
+			r.globalnetDisabledBrokers[globalnetKey] = time.Now()
+		}
+
+		if needsClustersetIPCIDR {
+			return false, r.brokerCIDRAllocationFailed(instance, "JoinRequestNotFound", errors.Errorf(
+				"the broker has no ClusterJoinRequest for cluster %q to allocate its ClustersetIP CIDR", instance.Spec.ClusterID))
+		}
+
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.BrokerCIDRAllocationFailedCondition)
+
+		return false, nil
+	}
+
+	if needsGlobalCIDR && request.Spec.GlobalnetClusterSize != instance.Spec.GlobalnetClusterSize {
+		request.Spec.GlobalnetClusterSize = instance.Spec.GlobalnetClusterSize
+
+		if err := brokerClient.Update(ctx, request); err != nil {
+			return false, r.brokerCIDRAllocationFailed(instance, "AllocationFailed",
+				errors.Wrapf(err, "error updating ClusterJoinRequest %q", request.Name))
+		}
+
+		return true, nil
+	}
+
+	condition := meta.FindStatusCondition(request.Status.Conditions, v1alpha1.ClusterJoinRequestReady)
+	if condition == nil || condition.ObservedGeneration != request.Generation {
+		return true, nil
+	}
+
+	switch condition.Reason {
+	case joinRequestInvalidSpec, joinRequestServiceAccountFailed, joinRequestAllocationFailed:
+		return false, r.brokerCIDRAllocationFailed(instance, "AllocationFailed",
+			errors.Errorf("the broker failed to allocate the cluster's CIDRs: %s", condition.Message))
+	}
+
+	if needsGlobalCIDR {
+		recordGlobalCIDRs(instance, request)
+
+		if request.Status.GlobalCIDR == "" {
+			log.Info("Globalnet isn't enabled on the broker, no global CIDR will be allocated",
+				"brokerNamespace", instance.Spec.BrokerK8sRemoteNamespace)
+
+			r.globalnetDisabledBrokers[globalnetKey] = time.Now()
+		}
+	}
+
+	if needsClustersetIPCIDR {
+		if request.Status.ClustersetIPCIDR == "" {
+			return false, r.brokerCIDRAllocationFailed(instance, "AllocationFailed",
+				errors.New("the broker didn't allocate a ClustersetIP CIDR, service discovery may not be enabled on it"))
+		}
+
+		if instance.Status.ClustersetIPCIDR != request.Status.ClustersetIPCIDR {
+			log.Info("Allocated the ClustersetIP CIDR from the broker", "ClustersetIPCIDR", request.Status.ClustersetIPCIDR)
+		}
+
+		instance.Status.ClustersetIPCIDR = request.Status.ClustersetIPCIDR
+	}
+
+	meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.BrokerCIDRAllocationFailedCondition)
+
+	return false, nil
+}
+
+// findClusterJoinRequest returns the cluster's ClusterJoinRequest on the broker, or nil if there isn't one. The
+// requests may have been created by the broker or by an administrator, so they're found by cluster ID.
+func findClusterJoinRequest(ctx context.Context, brokerClient client.Client, spec *v1alpha1.SubmarinerSpec,
+) (*v1alpha1.ClusterJoinRequest, error) {
+	requests := &v1alpha1.ClusterJoinRequestList{}
+
+	err := brokerClient.List(ctx, requests, client.InNamespace(spec.BrokerK8sRemoteNamespace))
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the ClusterJoinRequests on the broker")
+	}
+
+	for i := range requests.Items {
+		if requests.Items[i].Spec.ClusterID == spec.ClusterID && requests.Items[i].DeletionTimestamp == nil {
+			return &requests.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recordGlobalCIDRs records the global CIDRs the broker allocated to the cluster, as given in its ClusterJoinRequest.
+func recordGlobalCIDRs(instance *v1alpha1.Submariner, request *v1alpha1.ClusterJoinRequest) {
+	if request.Status.GlobalCIDR != "" && (instance.Status.GlobalCIDR != request.Status.GlobalCIDR ||
+		!slices.Equal(instance.Status.AdditionalGlobalCIDRs, request.Status.AdditionalGlobalCIDRs)) {
+		log.Info("Allocated the global CIDR from the broker", "GlobalCIDR", request.Status.GlobalCIDR,
+			"AdditionalGlobalCIDRs", request.Status.AdditionalGlobalCIDRs)
+	}
+
+	instance.Status.GlobalCIDR = request.Status.GlobalCIDR
+	instance.Status.AdditionalGlobalCIDRs = slices.Clone(request.Status.AdditionalGlobalCIDRs)
+}
+
+// recordSpecifiedCIDRs records the CIDRs specified in the spec, if any, as the cluster's current CIDRs in the status.
+func recordSpecifiedCIDRs(instance *v1alpha1.Submariner) {
+	if instance.Spec.GlobalCIDR != "" {
+		instance.Status.GlobalCIDR = instance.Spec.GlobalCIDR
+		instance.Status.AdditionalGlobalCIDRs = instance.Spec.AdditionalGlobalCIDRs
+	}
+
+	if instance.Spec.ClustersetIPCIDR != "" {
+		instance.Status.ClustersetIPCIDR = instance.Spec.ClustersetIPCIDR
+	}
+}
+
+func globalnetBrokerKey(apiServer, namespace string) string {
+	return apiServer + "/" + namespace
+}
+
+// isGlobalnetDisabledOn returns true if globalnet was recently found to be disabled on the given broker.
+func (r *Reconciler) isGlobalnetDisabledOn(globalnetKey string) bool {
+	disabledAt, found := r.globalnetDisabledBrokers[globalnetKey]
+	if found && time.Since(disabledAt) >= globalnetDisabledRecheckInterval {
+		delete(r.globalnetDisabledBrokers, globalnetKey)
+		return false
+	}
+
+	return found
+}
+
+// forgetGlobalnetDisabledBrokers clears what's known of globalnet on the brokers used by the given Submariner, so that
+// they're checked again if it's re-created.
+func (r *Reconciler) forgetGlobalnetDisabledBrokers(instance *v1alpha1.Submariner) {
+	for _, endpoint := range brokerEndpoints(&instance.Spec) {
+		delete(r.globalnetDisabledBrokers, globalnetBrokerKey(endpoint.ApiServer, instance.Spec.BrokerK8sRemoteNamespace))
+	}
+}
+
+func (r *Reconciler) brokerCIDRAllocationFailed(instance *v1alpha1.Submariner, reason string, err error) error {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.BrokerCIDRAllocationFailedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: instance.Generation,
+	})
+
+	return err
+}
+
+// globalCIDRSize returns the number of addresses in the given global CIDR, or 0 if there isn't a valid one.
+func globalCIDRSize(globalCIDR string) uint {
+	_, network, err := net.ParseCIDR(globalCIDR)
+	if err != nil {
+		return 0
+	}
+
+	ones, bits := network.Mask.Size()
+	if bits-ones >= strconv.IntSize {
+		return 0
+	}
+
+	return 1 << (bits - ones)
+}
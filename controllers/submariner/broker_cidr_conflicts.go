@@ -0,0 +1,110 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package submariner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	cidrsOverlapReason  = "OverlappingCIDRs"
+	cidrsDisjointReason = "NoOverlappingCIDRs"
+	cidrsInvalidReason  = "InvalidCIDRs"
+)
+
+// checkCIDRConflicts checks whether the cluster and service CIDRs published by member clusters which don't use globalnet
+// overlap, and reports any conflicts in the broker's status. Clusters are only published with the connectivity component.
+func (r *BrokerReconciler) checkCIDRConflicts(ctx context.Context, broker *v1alpha1.Broker) error {
+	if !brokerHasComponent(broker, v1alpha1.ComponentConnectivity) {
+		broker.Status.CIDRConflicts = nil
+		meta.RemoveStatusCondition(&broker.Status.Conditions, v1alpha1.CIDRsOverlapCondition)
+
+		return nil
+	}
+
+	clusters := &submv1.ClusterList{}
+	if err := r.Client.List(ctx, clusters, client.InNamespace(broker.Namespace)); err != nil {
+		return errors.Wrap(err, "error listing Clusters")
+	}
+
+	infoMap := map[string]*cidr.ClusterInfo{}
+
+	for i := range clusters.Items {
+		spec := &clusters.Items[i].Spec
+
+		// Globalnet clusters are expected to overlap.
+		if len(spec.GlobalCIDR) > 0 {
+			continue
+		}
+
+		infoMap[spec.ClusterID] = &cidr.ClusterInfo{
+			ClusterID: spec.ClusterID,
+			CIDRs:     slices.Concat(spec.ClusterCIDR, spec.ServiceCIDR),
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.CIDRsOverlapCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             cidrsDisjointReason,
+		Message:            "The CIDRs of the member clusters don't overlap",
+		ObservedGeneration: broker.Generation,
+	}
+
+	overlaps, err := cidr.FindOverlaps(infoMap)
+
+	broker.Status.CIDRConflicts = nil
+
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = cidrsInvalidReason
+		condition.Message = err.Error()
+	case len(overlaps) > 0:
+		messages := make([]string, len(overlaps))
+
+		for i := range overlaps {
+			broker.Status.CIDRConflicts = append(broker.Status.CIDRConflicts, v1alpha1.CIDRConflict(overlaps[i]))
+			messages[i] = cidrConflictMessage(&broker.Status.CIDRConflicts[i])
+		}
+
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = cidrsOverlapReason
+		condition.Message = strings.Join(messages, "; ")
+	}
+
+	meta.SetStatusCondition(&broker.Status.Conditions, condition)
+
+	return nil
+}
+
+func cidrConflictMessage(conflict *v1alpha1.CIDRConflict) string {
+	return fmt.Sprintf("CIDR %s of cluster %q overlaps with CIDR %s of cluster %q", conflict.CIDR, conflict.ClusterID,
+		conflict.OtherCIDR, conflict.OtherClusterID)
+}
@@ -0,0 +1,94 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package submariner
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// reportCIDRPools reports the utilization of the broker's globalnet and clustersetIP supernets in its status and
+// metrics. This is informational, so failures are logged rather than returned.
+func (r *BrokerReconciler) reportCIDRPools(ctx context.Context, broker *v1alpha1.Broker) {
+	broker.Status.GlobalnetCIDRPool = nil
+	broker.Status.ClustersetIPCIDRPool = nil
+
+	globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, r.Client, broker.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "Error retrieving the globalnet CIDR pool", "namespace", broker.Namespace)
+	}
+
+	if err == nil && globalnetInfo.Enabled {
+		broker.Status.GlobalnetCIDRPool = cidrPoolStatus(broker.Namespace, v1alpha1.CIDRAllocationPoolGlobalnet, &globalnetInfo.Info)
+	} else {
+		clearCIDRPool(broker.Namespace, v1alpha1.CIDRAllocationPoolGlobalnet)
+	}
+
+	clustersetIPInfo, _, err := clustersetip.GetClustersetIPNetworks(ctx, r.Client, broker.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "Error retrieving the clustersetIP CIDR pool", "namespace", broker.Namespace)
+	}
+
+	if err == nil && clustersetIPInfo.Enabled {
+		broker.Status.ClustersetIPCIDRPool = cidrPoolStatus(broker.Namespace, v1alpha1.CIDRAllocationPoolClustersetIP,
+			&clustersetIPInfo.Info)
+	} else {
+		clearCIDRPool(broker.Namespace, v1alpha1.CIDRAllocationPoolClustersetIP)
+	}
+}
+
+func cidrPoolStatus(namespace, pool string, info *cidr.Info) *v1alpha1.CIDRPoolStatus {
+	utilization, err := cidr.GetUtilization(info, nil, 1)
+	if err != nil {
+		log.Error(err, "Error computing the CIDR pool utilization", "namespace", namespace, "pool", pool)
+		clearCIDRPool(namespace, pool)
+
+		return nil
+	}
+
+	recordCIDRPool(namespace, pool, utilization)
+
+	status := &v1alpha1.CIDRPoolStatus{
+		CIDR:                 info.CIDR,
+		TotalAddresses:       utilization.TotalAddresses.String(),
+		UsedAddresses:        utilization.UsedAddresses.String(),
+		AvailableAllocations: utilization.AvailableAllocations.String(),
+		FragmentationPercent: int32(utilization.Fragmentation * 100),
+	}
+
+	if info.AllocationSize != nil {
+		status.AllocationSize = info.AllocationSize.String()
+	}
+
+	if len(utilization.NextAllocations) > 0 {
+		status.NextAllocation = utilization.NextAllocations[0]
+	}
+
+	return status
+}
+
+func bigToFloat(i *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(i).Float64()
+	return f
+}
@@ -0,0 +1,82 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateGlobalnetAllocations checks the global CIDRs allocated to member clusters against the broker's globalnet pool.
+// Allocations which couldn't have been made from the pool, such as those made out of band or before the pool was
+// changed, are released; clusters with a ClusterJoinRequest are then allocated valid CIDRs when it's next processed.
+func (r *BrokerReconciler) validateGlobalnetAllocations(ctx context.Context, broker *v1alpha1.Broker) error {
+	if !brokerHasComponent(broker, v1alpha1.ComponentConnectivity) {
+		return nil
+	}
+
+	globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, r.Client, broker.Namespace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error retrieving the globalnet pool")
+	}
+
+	if !globalnetInfo.Enabled {
+		return nil
+	}
+
+	allocations := &v1alpha1.CIDRAllocationList{}
+
+	err = r.Client.List(ctx, allocations, client.InNamespace(broker.Namespace),
+		client.MatchingLabels{cidr.PoolLabel: v1alpha1.CIDRAllocationPoolGlobalnet})
+	if err != nil {
+		return errors.Wrap(err, "error listing the globalnet CIDRAllocations")
+	}
+
+	for i := range allocations.Items {
+		allocation := &allocations.Items[i]
+
+		for _, allocated := range allocation.Spec.CIDRs {
+			err := cidr.ValidateAllocation(globalnetInfo.CIDR, allocated)
+			if err == nil {
+				continue
+			}
+
+			log.Info("Releasing the invalid global CIDR allocation of member cluster", "clusterID", allocation.Spec.ClusterID,
+				"CIDRs", allocation.Spec.CIDRs, "reason", err.Error())
+
+			if err := globalnet.Release(ctx, r.AllocationClient, broker.Namespace, allocation.Spec.ClusterID); err != nil {
+				return err //nolint:wrapcheck // Errors are already wrapped
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Set on a member cluster's heartbeat Lease when the broker finds it stale; it's cleared when the cluster renews the Lease.
+	staleSinceAnnotation = "submariner.io/stale-since"
+
+	// Set on a member cluster's heartbeat Lease by the cluster itself when it's uninstalled, so that the broker removes
+	// its resources and releases its CIDRs straight away. Member clusters can only release their own CIDRAllocations.
+	departedAnnotation = "submariner.io/departed"
+
+	// When stale cluster detection is enabled, the heartbeats are checked at this interval.
+	staleClusterCheckInterval = time.Minute
+)
+
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=submariner.io,resources=clusters;endpoints,verbs=get;list;watch;delete
+
+// collectStaleClusters removes the broker resources of the member clusters which have departed. If stale cluster
+// detection is enabled, it also marks the member clusters which haven't renewed their heartbeat within the broker's stale
+// cluster timeout, and removes the broker resources of those which have remained stale for the grace period. The
+// remaining stale clusters are reported in the broker's status. It returns the interval after which the check should be
+// repeated, or 0 if stale cluster detection is disabled.
+func (r *BrokerReconciler) collectStaleClusters(ctx context.Context, broker *v1alpha1.Broker) (time.Duration, error) {
+	broker.Status.StaleClusters = nil
+
+	leases := &coordinationv1.LeaseList{}
+
+	err := r.Client.List(ctx, leases, client.InNamespace(broker.Namespace), client.HasLabels{federate.ClusterIDLabelKey})
+	if err != nil {
+		return 0, errors.Wrap(err, "error listing the cluster heartbeat Leases")
+	}
+
+	var timeout, gracePeriod time.Duration
+
+	if broker.Spec.StaleClusterTimeout != nil {
+		timeout = broker.Spec.StaleClusterTimeout.Duration
+
+		gracePeriod = timeout
+		if broker.Spec.StaleClusterGracePeriod != nil {
+			gracePeriod = broker.Spec.StaleClusterGracePeriod.Duration
+		}
+	}
+
+	now := time.Now()
+
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		clusterID := lease.Labels[federate.ClusterIDLabelKey]
+
+		// The Lease must be the cluster's own heartbeat, which only the cluster's ServiceAccount can update (see
+		// ensureClusterRole); the label alone could designate any cluster.
+		if lease.Name != names.ForClusterHeartbeat(clusterID) {
+			continue
+		}
+
+		if _, departed := lease.Annotations[departedAnnotation]; departed {
+			log.Info("Member cluster has departed", "clusterID", clusterID)
+
+			if err := r.removeClusterFromBroker(ctx, broker.Namespace, clusterID, lease); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		// Leases which the cluster has never renewed were created by the broker for clusters which don't maintain their
+		// heartbeat, such as those running operators which predate it; their staleness can't be determined.
+		if broker.Spec.StaleClusterTimeout == nil || lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil {
+			continue
+		}
+
+		lastHeartbeat := lease.Spec.RenewTime.Time
+
+		staleSince, marked := lease.Annotations[staleSinceAnnotation]
+
+		if now.Sub(lastHeartbeat) < timeout {
+			if marked {
+				delete(lease.Annotations, staleSinceAnnotation)
+
+				if err := r.Client.Update(ctx, lease); err != nil {
+					return 0, errors.Wrapf(err, "error clearing the stale mark of cluster %q", clusterID)
+				}
+			}
+
+			continue
+		}
+
+		staleTime, err := time.Parse(time.RFC3339, staleSince)
+		if !marked || err != nil {
+			staleTime = now
+
+			metav1.SetMetaDataAnnotation(&lease.ObjectMeta, staleSinceAnnotation, staleTime.Format(time.RFC3339))
+
+			if err := r.Client.Update(ctx, lease); err != nil {
+				return 0, errors.Wrapf(err, "error marking cluster %q as stale", clusterID)
+			}
+
+			log.Info("Member cluster is stale", "clusterID", clusterID, "lastHeartbeat", lastHeartbeat,
+				"removalTime", staleTime.Add(gracePeriod))
+		}
+
+		if now.Sub(staleTime) >= gracePeriod {
+			if err := r.removeClusterFromBroker(ctx, broker.Namespace, clusterID, lease); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		broker.Status.StaleClusters = append(broker.Status.StaleClusters, v1alpha1.StaleCluster{
+			ClusterID:     clusterID,
+			LastHeartbeat: metav1.NewTime(lastHeartbeat).Rfc3339Copy(),
+			StaleSince:    metav1.NewTime(staleTime).Rfc3339Copy(),
+		})
+	}
+
+	if broker.Spec.StaleClusterTimeout == nil {
+		return 0, nil
+	}
+
+	return staleClusterCheckInterval, nil
+}
+
+// removeClusterFromBroker deletes the member cluster's resources on the broker and releases its CIDRs. The heartbeat
+// Lease is deleted last so that the removal is retried if any step fails.
+func (r *BrokerReconciler) removeClusterFromBroker(ctx context.Context, namespace, clusterID string, lease *coordinationv1.Lease,
+) error {
+	log.Info("Removing the broker resources of member cluster", "clusterID", clusterID)
+
+	// The dataplane CRDs aren't installed on brokers without the connectivity component.
+	endpoints := &submv1.EndpointList{}
+	if err := r.Client.List(ctx, endpoints, client.InNamespace(namespace)); err != nil && !meta.IsNoMatchError(err) {
+		return errors.Wrap(err, "error listing Endpoints")
+	}
+
+	for i := range endpoints.Items {
+		if endpoints.Items[i].Spec.ClusterID == clusterID {
+			if err := r.deleteIfPresent(ctx, &endpoints.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	clusters := &submv1.ClusterList{}
+	if err := r.Client.List(ctx, clusters, client.InNamespace(namespace)); err != nil && !meta.IsNoMatchError(err) {
+		return errors.Wrap(err, "error listing Clusters")
+	}
+
+	for i := range clusters.Items {
+		if clusters.Items[i].Spec.ClusterID == clusterID {
+			if err := r.deleteIfPresent(ctx, &clusters.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Objects created for join requests are owned by the request and garbage collected with it.
+	joinRequests := &v1alpha1.ClusterJoinRequestList{}
+	if err := r.Client.List(ctx, joinRequests, client.InNamespace(namespace)); err != nil {
+		return errors.Wrap(err, "error listing ClusterJoinRequests")
+	}
+
+	for i := range joinRequests.Items {
+		if joinRequests.Items[i].Spec.ClusterID == clusterID {
+			if err := r.deleteIfPresent(ctx, &joinRequests.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The ServiceAccount may also have been created out of band; its token Secrets are removed along with it.
+	saName := names.ForClusterSA(clusterID)
+
+	err := r.deleteIfPresent(ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: namespace}})
+	if err != nil {
+		return err
+	}
+
+	for _, roleBindingName := range []string{saName, names.ForClusterRole(clusterID)} {
+		err = r.deleteIfPresent(ctx, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: roleBindingName, Namespace: namespace}})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = r.deleteIfPresent(ctx, &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: names.ForClusterRole(clusterID), Namespace: namespace}})
+	if err != nil {
+		return err
+	}
+
+	if err := r.releaseClusterCIDRs(ctx, namespace, clusterID); err != nil {
+		return err
+	}
+
+	return r.deleteIfPresent(ctx, lease)
+}
+
+func (r *BrokerReconciler) releaseClusterCIDRs(ctx context.Context, namespace, clusterID string) error {
+	if err := globalnet.Release(ctx, r.AllocationClient, namespace, clusterID); err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	return clustersetip.Release(ctx, r.AllocationClient, namespace, clusterID) //nolint:wrapcheck // Errors are already wrapped
+}
+
+func (r *BrokerReconciler) deleteIfPresent(ctx context.Context, obj client.Object) error {
+	err := r.Client.Delete(ctx, obj)
+	if err == nil || apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrapf(err, "error deleting %T %q", obj, obj.GetName())
+}
@@ -0,0 +1,127 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	"github.com/submariner-io/submariner-operator/pkg/crd"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	"github.com/submariner-io/submariner-operator/pkg/gateway"
+	"github.com/submariner-io/submariner-operator/pkg/lighthouse"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=submariner.io,resources=cidrallocations,verbs=get;list;watch;create;update;delete;deletecollection
+
+// reconcileBrokerComponents provisions the resources of the components enabled on the broker, and removes those owned by
+// the components which aren't. CRDs are cluster-wide and may be used by member deployments on the same cluster, so
+// they're left in place when a component is removed.
+func (r *BrokerReconciler) reconcileBrokerComponents(ctx context.Context, broker *v1alpha1.Broker) error {
+	crdUpdater := crd.UpdaterFromControllerClient(r.Client)
+
+	if brokerHasComponent(broker, v1alpha1.ComponentConnectivity) {
+		if err := r.ensureConnectivityComponent(ctx, crdUpdater, broker); err != nil {
+			return err
+		}
+	} else if err := r.removeCIDRPool(ctx, broker, v1alpha1.CIDRAllocationPoolGlobalnet, globalnet.DeleteConfigMap); err != nil {
+		return err
+	}
+
+	if brokerHasComponent(broker, v1alpha1.ComponentServiceDiscovery) {
+		if err := r.ensureServiceDiscoveryComponent(ctx, crdUpdater, broker); err != nil {
+			return err
+		}
+	} else if err := r.removeCIDRPool(ctx, broker, v1alpha1.CIDRAllocationPoolClustersetIP, clustersetip.DeleteConfigMap); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *BrokerReconciler) ensureConnectivityComponent(ctx context.Context, crdUpdater crd.Updater, broker *v1alpha1.Broker) error {
+	// Broker CRDs
+	err := gateway.Ensure(ctx, crdUpdater)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	if !r.watchingClusters && r.watchClusters != nil {
+		if err := r.watchClusters(); err != nil {
+			return errors.Wrap(err, "error watching Clusters")
+		}
+
+		r.watchingClusters = true
+	}
+
+	// Globalnet
+	err = globalnet.ValidateExistingGlobalNetworks(ctx, r.Client, broker.Namespace)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	err = globalnet.CreateConfigMap(ctx, r.Client, broker.Spec.GlobalnetEnabled, broker.Spec.GlobalnetCIDRRange,
+		broker.Spec.DefaultGlobalnetClusterSize, broker.Namespace)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	return globalnet.MigrateAllocations(ctx, r.Client, broker.Namespace) //nolint:wrapcheck // Errors are already wrapped
+}
+
+func (r *BrokerReconciler) ensureServiceDiscoveryComponent(ctx context.Context, crdUpdater crd.Updater, broker *v1alpha1.Broker) error {
+	// Lighthouse CRDs
+	_, err := lighthouse.Ensure(ctx, crdUpdater, lighthouse.BrokerCluster)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	// clustersetip
+	err = clustersetip.ValidateExistingClustersetIPNetworks(ctx, r.Client, broker.Namespace)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	err = clustersetip.CreateConfigMap(ctx, r.Client, broker.Spec.ClustersetIPEnabled, broker.Spec.ClustersetIPCIDRRange, 0,
+		broker.Namespace)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	return clustersetip.MigrateAllocations(ctx, r.Client, broker.Namespace) //nolint:wrapcheck // Errors are already wrapped
+}
+
+// removeCIDRPool deletes the given pool's ConfigMap and the CIDRAllocations made from it.
+func (r *BrokerReconciler) removeCIDRPool(ctx context.Context, broker *v1alpha1.Broker, pool string,
+	deleteConfigMap func(context.Context, client.Client, string) error,
+) error {
+	if err := deleteConfigMap(ctx, r.Client, broker.Namespace); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting the %s ConfigMap", pool)
+	}
+
+	err := r.Client.DeleteAllOf(ctx, &v1alpha1.CIDRAllocation{}, client.InNamespace(broker.Namespace),
+		client.MatchingLabels{cidr.PoolLabel: pool})
+
+	return errors.Wrapf(err, "error deleting the %s CIDRAllocations", pool)
+}
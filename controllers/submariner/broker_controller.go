@@ -20,25 +20,37 @@ package submariner
 
 import (
 	"context"
+	"slices"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/submariner-operator/api/v1alpha1"
-	"github.com/submariner-io/submariner-operator/pkg/crd"
-	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
-	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
-	"github.com/submariner-io/submariner-operator/pkg/gateway"
-	"github.com/submariner-io/submariner-operator/pkg/lighthouse"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // BrokerReconciler reconciles a Broker object.
 type BrokerReconciler struct {
 	Client client.Client
-	Config *rest.Config
+	// An uncached client, for CIDR allocations which must be checked against the latest data.
+	AllocationClient client.Client
+	Config           *rest.Config
+
+	// Clusters are watched once the connectivity component has installed their CRD.
+	watchingClusters bool
+	watchClusters    func() error
 }
 
 //+kubebuilder:rbac:groups=submariner.io,resources=brokers,verbs=get;list;watch;create;update;patch;delete
@@ -68,50 +80,110 @@ func (r *BrokerReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
-	// Broker CRDs
-	crdUpdater := crd.UpdaterFromControllerClient(r.Client)
-
-	err = gateway.Ensure(ctx, crdUpdater)
+	err = r.reconcileBrokerComponents(ctx, instance)
 	if err != nil {
-		return ctrl.Result{}, err //nolint:wrapcheck // Errors are already wrapped
+		return ctrl.Result{}, err
 	}
 
-	// Lighthouse CRDs
-	_, err = lighthouse.Ensure(ctx, crdUpdater, lighthouse.BrokerCluster)
+	err = r.reconcileBrokerRBAC(ctx, instance)
 	if err != nil {
-		return ctrl.Result{}, err //nolint:wrapcheck // Errors are already wrapped
+		return ctrl.Result{}, err
 	}
 
-	// Globalnet
-	err = globalnet.ValidateExistingGlobalNetworks(ctx, r.Client, request.Namespace)
+	// Cluster join requests; failures are reported once the other clusters have been taken care of
+	pending, joinErr := r.reconcileClusterJoinRequests(ctx, instance)
+
+	// Departed clusters
+	initialStatus := instance.Status.DeepCopy()
+
+	staleClusterCheckInterval, err := r.collectStaleClusters(ctx, instance)
 	if err != nil {
-		return ctrl.Result{}, err //nolint:wrapcheck // Errors are already wrapped
+		return ctrl.Result{}, err
 	}
 
-	err = globalnet.CreateConfigMap(ctx, r.Client, instance.Spec.GlobalnetEnabled, instance.Spec.GlobalnetCIDRRange,
-		instance.Spec.DefaultGlobalnetClusterSize, request.Namespace)
-	if err != nil {
-		return ctrl.Result{}, err //nolint:wrapcheck // Errors are already wrapped
+	// Global CIDRs allocated by member clusters
+	if err := r.validateGlobalnetAllocations(ctx, instance); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// clustersetip
-	err = clustersetip.ValidateExistingClustersetIPNetworks(ctx, r.Client, request.Namespace)
-	if err != nil {
-		return ctrl.Result{}, err //nolint:wrapcheck // Errors are already wrapped
+	// CIDR pools, after any stale cluster's CIDRs have been released
+	r.reportCIDRPools(ctx, instance)
+
+	// Overlapping member cluster CIDRs
+	if err := r.checkCIDRConflicts(ctx, instance); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	err = clustersetip.CreateConfigMap(ctx, r.Client, instance.Spec.ClustersetIPEnabled,
-		instance.Spec.ClustersetIPCIDRRange, 0, request.Namespace)
-	if err != nil {
-		return ctrl.Result{}, err //nolint:wrapcheck // Errors are already wrapped
+	if !equality.Semantic.DeepEqual(initialStatus, &instance.Status) {
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "error updating the Broker status")
+		}
+	}
+
+	if joinErr != nil {
+		return ctrl.Result{}, joinErr
 	}
 
-	return ctrl.Result{}, nil
+	if pending {
+		return ctrl.Result{RequeueAfter: joinRequestRetryInterval}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: staleClusterCheckInterval}, nil
 }
 
-//nolint:wrapcheck // No need to wrap here.
 func (r *BrokerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	brokerController, err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Broker{}).
-		Complete(r)
+		Owns(&corev1.ServiceAccount{}).
+		// Member cluster ServiceAccounts may be created out of band; they're granted access to their own resources.
+		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(r.brokersInNamespace),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				_, isClusterSA := names.ClusterIDForSA(obj.GetName())
+				return isClusterSA
+			}))).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(&v1alpha1.ClusterJoinRequest{}, handler.EnqueueRequestsFromMapFunc(r.brokersInNamespace)).
+		Watches(&v1alpha1.CIDRAllocation{}, handler.EnqueueRequestsFromMapFunc(r.brokersInNamespace)).
+		// Heartbeat renewals are only of interest to the periodic stale cluster check.
+		Watches(&coordinationv1.Lease{}, handler.EnqueueRequestsFromMapFunc(r.brokersInNamespace),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				_, departed := obj.GetAnnotations()[departedAnnotation]
+				return departed
+			}))).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "error building the Broker controller")
+	}
+
+	r.watchClusters = func() error {
+		//nolint:wrapcheck // No need to wrap here.
+		return brokerController.Watch(source.Kind[client.Object](mgr.GetCache(), &submv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.brokersInNamespace)))
+	}
+
+	return nil
+}
+
+// brokersInNamespace maps an object to the Brokers in its namespace.
+func (r *BrokerReconciler) brokersInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	brokers := &v1alpha1.BrokerList{}
+
+	if err := r.Client.List(ctx, brokers, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Error listing Brokers", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(brokers.Items))
+	for i := range brokers.Items {
+		requests[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&brokers.Items[i])}
+	}
+
+	return requests
+}
+
+// brokerHasComponent returns true if the given component is enabled on the broker; all components are enabled
+// if none are listed.
+func brokerHasComponent(broker *v1alpha1.Broker, component string) bool {
+	return len(broker.Spec.Components) == 0 || slices.Contains(broker.Spec.Components, component)
 }
@@ -19,14 +19,31 @@ limitations under the License.
 package submariner_test
 
 import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/federate"
 	"github.com/submariner-io/submariner-operator/api/v1alpha1"
 	submarinerController "github.com/submariner-io/submariner-operator/controllers/submariner"
 	"github.com/submariner-io/submariner-operator/controllers/test"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
 	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	opnames "github.com/submariner-io/submariner-operator/pkg/names"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -61,7 +78,8 @@ var _ = Describe("Broker controller tests", func() {
 		t.JustBeforeEach()
 
 		t.Controller = &submarinerController.BrokerReconciler{
-			Client: t.ScopedClient,
+			Client:           t.ScopedClient,
+			AllocationClient: t.ScopedClient,
 		}
 	})
 
@@ -71,7 +89,101 @@ var _ = Describe("Broker controller tests", func() {
 		globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, t.ScopedClient, submarinerNamespace)
 		Expect(err).To(Succeed())
 		Expect(globalnetInfo.CIDR).To(Equal(broker.Spec.GlobalnetCIDRRange))
-		Expect(globalnetInfo.AllocationSize).To(Equal(broker.Spec.DefaultGlobalnetClusterSize))
+		Expect(globalnetInfo.AllocationSize.String()).To(Equal(strconv.FormatUint(uint64(broker.Spec.DefaultGlobalnetClusterSize), 10)))
+	})
+
+	When("member clusters have allocated global CIDRs", func() {
+		newAllocation := func(clusterID, globalCIDR string) *v1alpha1.CIDRAllocation {
+			return &v1alpha1.CIDRAllocation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, clusterID),
+					Namespace: submarinerNamespace,
+					Labels:    map[string]string{cidr.PoolLabel: v1alpha1.CIDRAllocationPoolGlobalnet},
+				},
+				Spec: v1alpha1.CIDRAllocationSpec{
+					ClusterID: clusterID,
+					Pool:      v1alpha1.CIDRAllocationPoolGlobalnet,
+					CIDRs:     []string{globalCIDR},
+				},
+			}
+		}
+
+		assertAllocation := func(ctx context.Context, clusterID string) {
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{
+				Namespace: submarinerNamespace,
+				Name:      opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, clusterID),
+			}, &v1alpha1.CIDRAllocation{})).To(Succeed())
+		}
+
+		assertNoAllocation := func(ctx context.Context, clusterID string) {
+			err := t.ScopedClient.Get(ctx, client.ObjectKey{
+				Namespace: submarinerNamespace,
+				Name:      opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, clusterID),
+			}, &v1alpha1.CIDRAllocation{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "Expected a NotFound error, got %v", err)
+		}
+
+		BeforeEach(func() {
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs,
+				newAllocation("east", "168.254.0.0/19"),
+				newAllocation("west", "168.0.0.0/8"),
+				newAllocation("south", "168.254.0.0/16"),
+				newAllocation("north", "168.254.64.0/19"))
+		})
+
+		It("should release those which couldn't have been allocated from the globalnet pool", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			assertAllocation(ctx, "east")
+			assertAllocation(ctx, "north")
+			assertNoAllocation(ctx, "west")
+			assertNoAllocation(ctx, "south")
+		})
+	})
+
+	When("the globalnet ConfigMap records allocations", func() {
+		BeforeEach(func() {
+			globalnetConfigMap, err := globalnet.NewGlobalnetConfigMap(true, broker.Spec.GlobalnetCIDRRange,
+				broker.Spec.DefaultGlobalnetClusterSize, submarinerNamespace)
+			Expect(err).To(Succeed())
+			Expect(cidr.AddClusterInfoData(globalnetConfigMap, cidr.ClusterInfo{
+				ClusterID: "east",
+				CIDRs:     []string{"168.254.0.0/19"},
+			})).To(Succeed())
+
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs, globalnetConfigMap)
+		})
+
+		It("should migrate them to CIDRAllocations", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			allocation := &v1alpha1.CIDRAllocation{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{
+				Namespace: submarinerNamespace,
+				Name:      opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, "east"),
+			}, allocation)).To(Succeed())
+			Expect(allocation.Spec.CIDRs).To(Equal([]string{"168.254.0.0/19"}))
+
+			configMap, err := globalnet.GetConfigMap(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+			Expect(configMap.Data).ToNot(HaveKey(cidr.ClusterInfoKey))
+		})
+
+		It("should report the globalnet CIDR pool utilization", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			updated := &v1alpha1.Broker{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(broker), updated)).To(Succeed())
+			Expect(updated.Status.GlobalnetCIDRPool).To(Equal(&v1alpha1.CIDRPoolStatus{
+				CIDR:                 broker.Spec.GlobalnetCIDRRange,
+				AllocationSize:       strconv.FormatUint(uint64(broker.Spec.DefaultGlobalnetClusterSize), 10),
+				TotalAddresses:       "65536",
+				UsedAddresses:        "8192",
+				AvailableAllocations: "7",
+				NextAllocation:       "168.254.32.0/19",
+			}))
+			Expect(updated.Status.ClustersetIPCIDRPool).To(BeNil())
+		})
 	})
 
 	It("should create the CRDs", func(ctx SpecContext) {
@@ -84,6 +196,872 @@ var _ = Describe("Broker controller tests", func() {
 		Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Name: "serviceimports.multicluster.x-k8s.io"}, crd)).To(Succeed())
 	})
 
+	It("should create the broker RBAC owned by the Broker", func(ctx SpecContext) {
+		t.AssertReconcileSuccess(ctx)
+
+		for _, name := range []string{"submariner-k8s-broker-admin", "submariner-k8s-broker-client"} {
+			sa := &corev1.ServiceAccount{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: name}, sa)).To(Succeed())
+			Expect(metav1.IsControlledBy(sa, broker)).To(BeTrue())
+
+			roleBinding := &rbacv1.RoleBinding{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: name}, roleBinding)).To(Succeed())
+			Expect(metav1.IsControlledBy(roleBinding, broker)).To(BeTrue())
+			Expect(roleBinding.Subjects).To(Equal([]rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      name,
+				Namespace: submarinerNamespace,
+			}}))
+
+			role := &rbacv1.Role{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: roleBinding.RoleRef.Name},
+				role)).To(Succeed())
+			Expect(metav1.IsControlledBy(role, broker)).To(BeTrue())
+			Expect(role.Rules).To(ContainElement(HaveField("Resources", ContainElement("endpoints"))))
+			Expect(role.Rules).To(ContainElement(HaveField("Resources", ContainElement("serviceimports"))))
+		}
+	})
+
+	When("the broker Roles are outdated", func() {
+		BeforeEach(func() {
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs, &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "submariner-k8s-broker-cluster", Namespace: submarinerNamespace},
+				Rules: []rbacv1.PolicyRule{{
+					APIGroups: []string{"submariner.io"},
+					Resources: []string{"clusters"},
+					Verbs:     []string{"get"},
+				}},
+			})
+		})
+
+		It("should update them", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			role := &rbacv1.Role{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: "submariner-k8s-broker-cluster"},
+				role)).To(Succeed())
+			Expect(role.Rules).To(ContainElement(HaveField("Resources", ContainElement("cidrallocations"))))
+		})
+	})
+
+	When("only the service-discovery component is enabled", func() {
+		BeforeEach(func() {
+			broker.Spec.Components = []string{v1alpha1.ComponentServiceDiscovery}
+			broker.Spec.ClustersetIPEnabled = true
+			broker.Spec.ClustersetIPCIDRRange = "243.0.0.0/16"
+		})
+
+		It("should only provision the service discovery resources", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			crd := &apiextensions.CustomResourceDefinition{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Name: "serviceimports.multicluster.x-k8s.io"}, crd)).To(Succeed())
+
+			err := t.ScopedClient.Get(ctx, client.ObjectKey{Name: "endpoints.submariner.io"}, crd)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			_, err = clustersetip.GetConfigMap(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+
+			_, err = globalnet.GetConfigMap(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			role := &rbacv1.Role{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: "submariner-k8s-broker-cluster"},
+				role)).To(Succeed())
+			Expect(role.Rules).To(ContainElement(HaveField("Resources", ContainElement("serviceimports"))))
+			Expect(role.Rules).ToNot(ContainElement(HaveField("Resources", ContainElement("endpoints"))))
+
+			updated := &v1alpha1.Broker{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(broker), updated)).To(Succeed())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.CIDRsOverlapCondition)).To(BeNil())
+		})
+
+		Context("and the connectivity component was previously enabled", func() {
+			BeforeEach(func() {
+				globalnetConfigMap, err := globalnet.NewGlobalnetConfigMap(true, broker.Spec.GlobalnetCIDRRange,
+					broker.Spec.DefaultGlobalnetClusterSize, submarinerNamespace)
+				Expect(err).To(Succeed())
+
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs, globalnetConfigMap, &v1alpha1.CIDRAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, "east"),
+						Namespace: submarinerNamespace,
+						Labels:    map[string]string{cidr.PoolLabel: v1alpha1.CIDRAllocationPoolGlobalnet},
+					},
+				})
+			})
+
+			It("should delete the globalnet ConfigMap and allocations", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				_, err := globalnet.GetConfigMap(ctx, t.ScopedClient, submarinerNamespace)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				allocations := &v1alpha1.CIDRAllocationList{}
+				Expect(t.ScopedClient.List(ctx, allocations, client.InNamespace(submarinerNamespace))).To(Succeed())
+				Expect(allocations.Items).To(BeEmpty())
+			})
+		})
+	})
+
+	When("only the connectivity component is enabled", func() {
+		BeforeEach(func() {
+			broker.Spec.Components = []string{v1alpha1.ComponentConnectivity}
+		})
+
+		It("should only provision the connectivity resources", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			crd := &apiextensions.CustomResourceDefinition{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Name: "endpoints.submariner.io"}, crd)).To(Succeed())
+
+			err := t.ScopedClient.Get(ctx, client.ObjectKey{Name: "serviceimports.multicluster.x-k8s.io"}, crd)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			_, err = globalnet.GetConfigMap(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+
+			_, err = clustersetip.GetConfigMap(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	assertClusterRole := func(ctx context.Context, clusterID, joinRequestName string) {
+		saName := opnames.ForClusterSA(clusterID)
+		roleName := opnames.ForClusterRole(clusterID)
+
+		sa := &corev1.ServiceAccount{}
+		Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: saName}, sa)).To(Succeed())
+
+		role := &rbacv1.Role{}
+		Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: roleName}, role)).To(Succeed())
+		Expect(role.OwnerReferences).To(ContainElement(HaveField("UID", sa.UID)))
+		Expect(role.Rules).To(HaveLen(3))
+		Expect(role.Rules[0].Resources).To(Equal([]string{"leases"}))
+		Expect(role.Rules[0].ResourceNames).To(Equal([]string{opnames.ForClusterHeartbeat(clusterID)}))
+		Expect(role.Rules[1].Resources).To(Equal([]string{"clusterjoinrequests"}))
+		Expect(role.Rules[1].ResourceNames).To(Equal([]string{joinRequestName}))
+		Expect(role.Rules[2].Resources).To(Equal([]string{"cidrallocations"}))
+		Expect(role.Rules[2].ResourceNames).To(ConsistOf(
+			opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, clusterID),
+			opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolClustersetIP, clusterID)))
+
+		roleBinding := &rbacv1.RoleBinding{}
+		Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: roleName}, roleBinding)).To(Succeed())
+		Expect(roleBinding.OwnerReferences).To(ContainElement(HaveField("UID", sa.UID)))
+		Expect(roleBinding.RoleRef.Name).To(Equal(roleName))
+		Expect(roleBinding.Subjects).To(Equal([]rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      saName,
+			Namespace: submarinerNamespace,
+		}}))
+
+		lease := &coordinationv1.Lease{}
+		Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: opnames.ForClusterHeartbeat(clusterID)},
+			lease)).To(Succeed())
+		Expect(lease.Labels).To(HaveKeyWithValue(federate.ClusterIDLabelKey, clusterID))
+	}
+
+	When("a member cluster's ServiceAccount was created out of band", func() {
+		var eastClient client.Client
+
+		BeforeEach(func() {
+			for _, clusterID := range []string{"east", "west"} {
+				saName := opnames.ForClusterSA(clusterID)
+
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs,
+					&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+						Name:      saName,
+						Namespace: submarinerNamespace,
+						UID:       types.UID(clusterID + "-sa-uid"),
+					}},
+					// As created by subctl
+					&rbacv1.RoleBinding{
+						ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: submarinerNamespace},
+						RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "submariner-k8s-broker-cluster"},
+						Subjects: []rbacv1.Subject{{
+							Kind:      rbacv1.ServiceAccountKind,
+							Name:      saName,
+							Namespace: submarinerNamespace,
+						}},
+					})
+			}
+		})
+
+		JustBeforeEach(func(ctx SpecContext) {
+			// The ClusterJoinRequests created for the clusters await their ServiceAccount tokens.
+			t.AssertReconcileRequeue(ctx)
+
+			eastClient = test.NewServiceAccountClient(t.ScopedClient.(client.WithWatch), submarinerNamespace, opnames.ForClusterSA("east"))
+		})
+
+		It("should grant it access to its own heartbeat Lease, ClusterJoinRequest and CIDRAllocations", func(ctx SpecContext) {
+			assertClusterRole(ctx, "east", opnames.ForClusterJoinRequest("east"))
+			assertClusterRole(ctx, "west", opnames.ForClusterJoinRequest("west"))
+		})
+
+		It("should create a ClusterJoinRequest for it and allocate its CIDRs", func(ctx SpecContext) {
+			request := &v1alpha1.ClusterJoinRequest{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: opnames.ForClusterJoinRequest("east")},
+				request)).To(Succeed())
+			Expect(request.Spec.ClusterID).To(Equal("east"))
+			Expect(request.Status.GlobalCIDR).To(Equal("168.254.0.0/19"))
+		})
+
+		It("should only allow it to write its own heartbeat Lease", func(ctx SpecContext) {
+			lease := &coordinationv1.Lease{}
+			Expect(eastClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: opnames.ForClusterHeartbeat("east")},
+				lease)).To(Succeed())
+
+			lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now()))
+			Expect(eastClient.Update(ctx, lease)).To(Succeed())
+
+			westLease := &coordinationv1.Lease{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: opnames.ForClusterHeartbeat("west")},
+				westLease)).To(Succeed())
+
+			metav1.SetMetaDataAnnotation(&westLease.ObjectMeta, "submariner.io/departed", time.Now().Format(time.RFC3339))
+			err := eastClient.Update(ctx, westLease)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+
+			err = eastClient.Delete(ctx, westLease)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+
+			err = eastClient.Create(ctx, &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+				Name:      opnames.ForClusterHeartbeat("north"),
+				Namespace: submarinerNamespace,
+				Labels:    map[string]string{federate.ClusterIDLabelKey: "north"},
+			}})
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+		})
+
+		It("should only allow it to request its own CIDRs", func(ctx SpecContext) {
+			request := &v1alpha1.ClusterJoinRequest{}
+			Expect(eastClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: opnames.ForClusterJoinRequest("east")},
+				request)).To(Succeed())
+
+			request.Spec.GlobalnetClusterSize = 4096
+			Expect(eastClient.Update(ctx, request)).To(Succeed())
+
+			westRequest := &v1alpha1.ClusterJoinRequest{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: opnames.ForClusterJoinRequest("west")},
+				westRequest)).To(Succeed())
+
+			westRequest.Spec.GlobalnetClusterSize = 4096
+			err := eastClient.Update(ctx, westRequest)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+
+			err = eastClient.Create(ctx, &v1alpha1.ClusterJoinRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "north-join", Namespace: submarinerNamespace},
+				Spec:       v1alpha1.ClusterJoinRequestSpec{ClusterID: "north"},
+			})
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+
+			err = eastClient.Create(ctx, &v1alpha1.CIDRAllocation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      opnames.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, "north"),
+					Namespace: submarinerNamespace,
+				},
+				Spec: v1alpha1.CIDRAllocationSpec{
+					ClusterID: "north",
+					Pool:      v1alpha1.CIDRAllocationPoolGlobalnet,
+					CIDRs:     []string{"168.254.64.0/19"},
+				},
+			})
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+
+			configMap, err := globalnet.GetConfigMap(ctx, eastClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+
+			err = eastClient.Update(ctx, configMap)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+		})
+
+		It("should allow it to release its own CIDRs", func(ctx SpecContext) {
+			released, err := cidr.RemoveAllocation(ctx, eastClient, submarinerNamespace, v1alpha1.CIDRAllocationPoolGlobalnet, "east")
+			Expect(err).To(Succeed())
+			Expect(released).To(BeTrue())
+
+			_, err = cidr.RemoveAllocation(ctx, eastClient, submarinerNamespace, v1alpha1.CIDRAllocationPoolGlobalnet, "west")
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "Expected a forbidden error, got %v", err)
+		})
+	})
+
+	When("a ClusterJoinRequest is created", func() {
+		var joinRequest *v1alpha1.ClusterJoinRequest
+
+		BeforeEach(func() {
+			broker.Spec.BrokerK8sApiServer = "https://broker:6443"
+			broker.Spec.ClustersetIPEnabled = true
+			broker.Spec.ClustersetIPCIDRRange = "243.0.0.0/16"
+			broker.Spec.DefaultCustomDomains = []string{"supercluster.local"}
+
+			joinRequest = &v1alpha1.ClusterJoinRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "east-join",
+					Namespace: submarinerNamespace,
+				},
+				Spec: v1alpha1.ClusterJoinRequestSpec{
+					ClusterID: "east",
+				},
+			}
+
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs, joinRequest)
+		})
+
+		getJoinRequest := func(ctx context.Context) *v1alpha1.ClusterJoinRequest {
+			obj := &v1alpha1.ClusterJoinRequest{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(joinRequest), obj)).To(Succeed())
+
+			return obj
+		}
+
+		populateToken := func(ctx context.Context) {
+			tokenSecret := &corev1.Secret{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{
+				Namespace: submarinerNamespace,
+				Name:      opnames.ForClusterSA(joinRequest.Spec.ClusterID) + "-token",
+			}, tokenSecret)).To(Succeed())
+
+			tokenSecret.Data = map[string][]byte{
+				corev1.ServiceAccountTokenKey:  []byte("east-token"),
+				corev1.ServiceAccountRootCAKey: []byte("broker-ca"),
+			}
+
+			Expect(t.ScopedClient.Update(ctx, tokenSecret)).To(Succeed())
+		}
+
+		It("should create the cluster's ServiceAccount, token Secret and RoleBindings", func(ctx SpecContext) {
+			t.AssertReconcileRequeue(ctx)
+
+			saName := opnames.ForClusterSA(joinRequest.Spec.ClusterID)
+
+			sa := &corev1.ServiceAccount{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: saName}, sa)).To(Succeed())
+			Expect(sa.OwnerReferences).To(HaveLen(1))
+			Expect(sa.OwnerReferences[0].Name).To(Equal(joinRequest.Name))
+
+			tokenSecret := &corev1.Secret{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: saName + "-token"},
+				tokenSecret)).To(Succeed())
+			Expect(tokenSecret.Type).To(Equal(corev1.SecretTypeServiceAccountToken))
+			Expect(tokenSecret.Annotations).To(HaveKeyWithValue(corev1.ServiceAccountNameKey, saName))
+
+			roleBinding := &rbacv1.RoleBinding{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: saName}, roleBinding)).To(Succeed())
+			Expect(roleBinding.RoleRef.Name).To(Equal("submariner-k8s-broker-cluster"))
+			Expect(roleBinding.Subjects).To(Equal([]rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      saName,
+				Namespace: submarinerNamespace,
+			}}))
+
+			assertClusterRole(ctx, joinRequest.Spec.ClusterID, joinRequest.Name)
+
+			status := getJoinRequest(ctx).Status
+			Expect(status.ServiceAccount).To(Equal(saName))
+			Expect(status.JoinBundleSecret).To(BeEmpty())
+			Expect(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.ClusterJoinRequestReady)).To(BeTrue())
+		})
+
+		It("should allocate the cluster's CIDRs", func(ctx SpecContext) {
+			t.AssertReconcileRequeue(ctx)
+
+			status := getJoinRequest(ctx).Status
+
+			globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+			Expect(globalnetInfo.Clusters).To(HaveKey(joinRequest.Spec.ClusterID))
+			Expect(globalnetInfo.Clusters[joinRequest.Spec.ClusterID].CIDRs).To(Equal([]string{status.GlobalCIDR}))
+
+			clustersetIPInfo, _, err := clustersetip.GetClustersetIPNetworks(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+			Expect(clustersetIPInfo.Clusters).To(HaveKey(joinRequest.Spec.ClusterID))
+			Expect(clustersetIPInfo.Clusters[joinRequest.Spec.ClusterID].CIDRs).To(Equal([]string{status.ClustersetIPCIDR}))
+		})
+
+		It("should publish the join bundle once the ServiceAccount token is available", func(ctx SpecContext) {
+			t.AssertReconcileRequeue(ctx)
+
+			populateToken(ctx)
+
+			t.AssertReconcileSuccess(ctx)
+
+			status := getJoinRequest(ctx).Status
+			Expect(status.JoinBundleSecret).To(Equal(opnames.ForClusterJoinBundle(joinRequest.Spec.ClusterID)))
+			Expect(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ClusterJoinRequestReady)).To(BeTrue())
+
+			bundle := &corev1.Secret{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: status.JoinBundleSecret},
+				bundle)).To(Succeed())
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleBrokerK8sApiServer, []byte(broker.Spec.BrokerK8sApiServer)))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleBrokerK8sApiServerToken, []byte("east-token")))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleBrokerK8sCA,
+				[]byte(base64.StdEncoding.EncodeToString([]byte("broker-ca")))))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleBrokerK8sRemoteNamespace, []byte(submarinerNamespace)))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleClusterID, []byte(joinRequest.Spec.ClusterID)))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleGlobalCIDR, []byte(status.GlobalCIDR)))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleClustersetIPCIDR, []byte(status.ClustersetIPCIDR)))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleClustersetIPEnabled, []byte("true")))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleServiceDiscoveryEnabled, []byte("true")))
+			Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleCustomDomains, []byte("supercluster.local")))
+			Expect(bundle.Data).ToNot(HaveKey(v1alpha1.JoinBundleCeIPSecPSK))
+		})
+
+		Context("alongside an invalid ClusterJoinRequest", func() {
+			BeforeEach(func() {
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs, &v1alpha1.ClusterJoinRequest{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "anonymous-join",
+						Namespace: submarinerNamespace,
+					},
+				})
+			})
+
+			It("should still process the valid request and return an error", func(ctx SpecContext) {
+				t.AssertReconcileError(ctx)
+
+				status := getJoinRequest(ctx).Status
+				Expect(status.ServiceAccount).To(Equal(opnames.ForClusterSA(joinRequest.Spec.ClusterID)))
+				Expect(status.GlobalCIDR).ToNot(BeEmpty())
+
+				invalid := &v1alpha1.ClusterJoinRequest{}
+				Expect(t.ScopedClient.Get(ctx, client.ObjectKey{Namespace: submarinerNamespace, Name: "anonymous-join"},
+					invalid)).To(Succeed())
+				Expect(meta.FindStatusCondition(invalid.Status.Conditions, v1alpha1.ClusterJoinRequestReady).Reason).To(
+					Equal("InvalidSpec"))
+			})
+		})
+
+		Context("with a requested global CIDR", func() {
+			BeforeEach(func() {
+				joinRequest.Spec.GlobalCIDR = "168.254.64.0/19"
+			})
+
+			It("should assign it to the cluster", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				Expect(getJoinRequest(ctx).Status.GlobalCIDR).To(Equal(joinRequest.Spec.GlobalCIDR))
+			})
+		})
+
+		Context("with a requested global CIDR outside the globalnet pool", func() {
+			BeforeEach(func() {
+				joinRequest.Spec.GlobalCIDR = "242.0.0.0/19"
+			})
+
+			It("should fail the allocation", func(ctx SpecContext) {
+				t.AssertReconcileError(ctx)
+
+				request := getJoinRequest(ctx)
+				Expect(request.Status.GlobalCIDR).To(BeEmpty())
+
+				condition := meta.FindStatusCondition(request.Status.Conditions, v1alpha1.ClusterJoinRequestReady)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Reason).To(Equal("AllocationFailed"))
+				Expect(condition.Message).To(ContainSubstring("isn't within " + broker.Spec.GlobalnetCIDRRange))
+			})
+		})
+
+		Context("with a cluster size", func() {
+			BeforeEach(func() {
+				joinRequest.Spec.GlobalnetClusterSize = 4096
+			})
+
+			It("should allocate a global CIDR of that size", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				Expect(getJoinRequest(ctx).Status.GlobalCIDR).To(Equal("168.254.0.0/20"))
+			})
+
+			Context("which is subsequently changed", func() {
+				It("should reallocate the global CIDR with the new size", func(ctx SpecContext) {
+					t.AssertReconcileRequeue(ctx)
+
+					request := getJoinRequest(ctx)
+					request.Spec.GlobalnetClusterSize = 16384
+					Expect(t.ScopedClient.Update(ctx, request)).To(Succeed())
+
+					t.AssertReconcileRequeue(ctx)
+
+					Expect(getJoinRequest(ctx).Status.GlobalCIDR).To(Equal("168.254.0.0/18"))
+
+					globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, t.ScopedClient, submarinerNamespace)
+					Expect(err).To(Succeed())
+					Expect(globalnetInfo.Clusters[joinRequest.Spec.ClusterID].CIDRs).To(Equal([]string{"168.254.0.0/18"}))
+				})
+			})
+
+			Context("which can't be granted from the globalnet pool", func() {
+				BeforeEach(func() {
+					joinRequest.Spec.GlobalnetClusterSize = 1 << 17
+				})
+
+				It("should fail the allocation", func(ctx SpecContext) {
+					t.AssertReconcileError(ctx)
+
+					condition := meta.FindStatusCondition(getJoinRequest(ctx).Status.Conditions, v1alpha1.ClusterJoinRequestReady)
+					Expect(condition).ToNot(BeNil())
+					Expect(condition.Reason).To(Equal("AllocationFailed"))
+				})
+			})
+		})
+
+		Context("with a cluster size and several global CIDR blocks", func() {
+			BeforeEach(func() {
+				joinRequest.Spec.GlobalnetClusterSize = 4096
+				joinRequest.Spec.GlobalCIDRBlocks = 2
+			})
+
+			It("should allocate all the blocks with the requested size", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				status := getJoinRequest(ctx).Status
+				Expect(status.GlobalCIDR).To(Equal("168.254.0.0/20"))
+				Expect(status.AdditionalGlobalCIDRs).To(Equal([]string{"168.254.16.0/20"}))
+
+				populateToken(ctx)
+				t.AssertReconcileSuccess(ctx)
+
+				bundle := &corev1.Secret{}
+				Expect(t.ScopedClient.Get(ctx, client.ObjectKey{
+					Namespace: submarinerNamespace,
+					Name:      opnames.ForClusterJoinBundle(joinRequest.Spec.ClusterID),
+				}, bundle)).To(Succeed())
+				Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleAdditionalGlobalCIDRs, []byte("168.254.16.0/20")))
+			})
+		})
+
+		Context("and the broker has an IPsec PSK", func() {
+			BeforeEach(func() {
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "submariner-ipsec-psk",
+						Namespace: submarinerNamespace,
+					},
+					Data: map[string][]byte{"psk": []byte("secret-psk")},
+				})
+			})
+
+			It("should include it in the join bundle", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+				populateToken(ctx)
+				t.AssertReconcileSuccess(ctx)
+
+				bundle := &corev1.Secret{}
+				Expect(t.ScopedClient.Get(ctx, client.ObjectKey{
+					Namespace: submarinerNamespace,
+					Name:      opnames.ForClusterJoinBundle(joinRequest.Spec.ClusterID),
+				}, bundle)).To(Succeed())
+				Expect(bundle.Data).To(HaveKeyWithValue(v1alpha1.JoinBundleCeIPSecPSK,
+					[]byte(base64.StdEncoding.EncodeToString([]byte("secret-psk")))))
+			})
+		})
+	})
+
+	When("member clusters publish their CIDRs", func() {
+		newCluster := func(clusterID string, clusterCIDR, serviceCIDR string, globalCIDR ...string) *submarinerv1.Cluster {
+			return &submarinerv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterID, Namespace: submarinerNamespace},
+				Spec: submarinerv1.ClusterSpec{
+					ClusterID:   clusterID,
+					ClusterCIDR: []string{clusterCIDR},
+					ServiceCIDR: []string{serviceCIDR},
+					GlobalCIDR:  globalCIDR,
+				},
+			}
+		}
+
+		getBroker := func(ctx context.Context) *v1alpha1.Broker {
+			obj := &v1alpha1.Broker{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(broker), obj)).To(Succeed())
+
+			return obj
+		}
+
+		BeforeEach(func() {
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs,
+				newCluster("east", "10.0.0.0/16", "100.90.0.0/16"),
+				newCluster("north", "10.0.0.0/16", "100.90.0.0/16", "242.0.0.0/16"))
+		})
+
+		Context("and they don't overlap", func() {
+			BeforeEach(func() {
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs, newCluster("west", "10.1.0.0/16", "100.91.0.0/16"))
+			})
+
+			It("should report that there are no conflicts", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				updated := getBroker(ctx)
+				Expect(updated.Status.CIDRConflicts).To(BeEmpty())
+
+				condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.CIDRsOverlapCondition)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			})
+		})
+
+		Context("and the CIDRs of clusters without globalnet overlap", func() {
+			BeforeEach(func() {
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs, newCluster("west", "10.0.128.0/17", "100.91.0.0/16"))
+			})
+
+			It("should report the conflicts", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				updated := getBroker(ctx)
+				Expect(updated.Status.CIDRConflicts).To(Equal([]v1alpha1.CIDRConflict{{
+					ClusterID:      "east",
+					CIDR:           "10.0.0.0/16",
+					OtherClusterID: "west",
+					OtherCIDR:      "10.0.128.0/17",
+				}}))
+
+				condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.CIDRsOverlapCondition)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Message).To(ContainSubstring("west"))
+			})
+		})
+	})
+
+	When("stale cluster detection is enabled", func() {
+		var (
+			eastLease *coordinationv1.Lease
+			westLease *coordinationv1.Lease
+		)
+
+		newLease := func(clusterID string, renewTime time.Time) *coordinationv1.Lease {
+			return &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      opnames.ForClusterHeartbeat(clusterID),
+					Namespace: submarinerNamespace,
+					Labels:    map[string]string{federate.ClusterIDLabelKey: clusterID},
+				},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity: ptr.To(clusterID),
+					RenewTime:      ptr.To(metav1.NewMicroTime(renewTime)),
+				},
+			}
+		}
+
+		getLease := func(ctx context.Context, lease *coordinationv1.Lease) (*coordinationv1.Lease, error) {
+			obj := &coordinationv1.Lease{}
+			err := t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(lease), obj)
+
+			return obj, err
+		}
+
+		getBroker := func(ctx context.Context) *v1alpha1.Broker {
+			obj := &v1alpha1.Broker{}
+			Expect(t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(broker), obj)).To(Succeed())
+
+			return obj
+		}
+
+		BeforeEach(func() {
+			broker.Spec.StaleClusterTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+			broker.Spec.StaleClusterGracePeriod = &metav1.Duration{Duration: 30 * time.Minute}
+
+			eastLease = newLease("east", time.Now().Add(-time.Hour))
+			westLease = newLease("west", time.Now())
+
+			globalnetConfigMap, err := globalnet.NewGlobalnetConfigMap(true, broker.Spec.GlobalnetCIDRRange,
+				broker.Spec.DefaultGlobalnetClusterSize, submarinerNamespace)
+			Expect(err).To(Succeed())
+			Expect(cidr.AddClusterInfoData(globalnetConfigMap, cidr.ClusterInfo{
+				ClusterID: "east",
+				CIDRs:     []string{"168.254.0.0/19"},
+			})).To(Succeed())
+			Expect(cidr.AddClusterInfoData(globalnetConfigMap, cidr.ClusterInfo{
+				ClusterID: "west",
+				CIDRs:     []string{"168.254.32.0/19"},
+			})).To(Succeed())
+
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs, eastLease, westLease, globalnetConfigMap)
+
+			for _, clusterID := range []string{"east", "west"} {
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs,
+					&submarinerv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{Name: clusterID, Namespace: submarinerNamespace},
+						Spec:       submarinerv1.ClusterSpec{ClusterID: clusterID},
+					},
+					&submarinerv1.Endpoint{
+						ObjectMeta: metav1.ObjectMeta{Name: clusterID + "-submariner-cable", Namespace: submarinerNamespace},
+						Spec:       submarinerv1.EndpointSpec{ClusterID: clusterID},
+					},
+					&corev1.ServiceAccount{
+						ObjectMeta: metav1.ObjectMeta{Name: opnames.ForClusterSA(clusterID), Namespace: submarinerNamespace},
+					})
+			}
+		})
+
+		assertClusterResources := func(ctx context.Context, clusterID string, expectPresent bool) {
+			objs := []client.Object{
+				&submarinerv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterID, Namespace: submarinerNamespace}},
+				&submarinerv1.Endpoint{ObjectMeta: metav1.ObjectMeta{Name: clusterID + "-submariner-cable", Namespace: submarinerNamespace}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: opnames.ForClusterSA(clusterID), Namespace: submarinerNamespace}},
+			}
+
+			for _, obj := range objs {
+				err := t.ScopedClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+				if expectPresent {
+					Expect(err).To(Succeed())
+				} else {
+					Expect(apierrors.IsNotFound(err)).To(BeTrue(), "Expected %T %q to be deleted", obj, obj.GetName())
+				}
+			}
+
+			globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, t.ScopedClient, submarinerNamespace)
+			Expect(err).To(Succeed())
+
+			if expectPresent {
+				Expect(globalnetInfo.Clusters).To(HaveKey(clusterID))
+			} else {
+				Expect(globalnetInfo.Clusters).ToNot(HaveKey(clusterID))
+			}
+		}
+
+		It("should mark clusters which have stopped renewing their heartbeat as stale", func(ctx SpecContext) {
+			t.AssertReconcileRequeue(ctx)
+
+			lease, err := getLease(ctx, eastLease)
+			Expect(err).To(Succeed())
+			Expect(lease.Annotations).To(HaveKey("submariner.io/stale-since"))
+
+			lease, err = getLease(ctx, westLease)
+			Expect(err).To(Succeed())
+			Expect(lease.Annotations).ToNot(HaveKey("submariner.io/stale-since"))
+
+			staleClusters := getBroker(ctx).Status.StaleClusters
+			Expect(staleClusters).To(HaveLen(1))
+			Expect(staleClusters[0].ClusterID).To(Equal("east"))
+			Expect(staleClusters[0].LastHeartbeat.Time).To(BeTemporally("~", eastLease.Spec.RenewTime.Time, time.Second))
+
+			assertClusterResources(ctx, "east", true)
+			assertClusterResources(ctx, "west", true)
+		})
+
+		Context("and the grace period has elapsed", func() {
+			BeforeEach(func() {
+				eastLease.Annotations = map[string]string{
+					"submariner.io/stale-since": time.Now().Add(-time.Hour).Format(time.RFC3339),
+				}
+			})
+
+			It("should remove the stale cluster's broker resources and release its CIDRs", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				assertClusterResources(ctx, "east", false)
+				assertClusterResources(ctx, "west", true)
+
+				_, err := getLease(ctx, eastLease)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				Expect(getBroker(ctx).Status.StaleClusters).To(BeEmpty())
+			})
+		})
+
+		Context("and a cluster has departed", func() {
+			BeforeEach(func() {
+				westLease.Annotations = map[string]string{
+					"submariner.io/departed": time.Now().Format(time.RFC3339),
+				}
+			})
+
+			It("should remove its broker resources and release its CIDRs straight away", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				assertClusterResources(ctx, "west", false)
+				assertClusterResources(ctx, "east", true)
+
+				_, err := getLease(ctx, westLease)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			Context("with stale cluster detection disabled", func() {
+				BeforeEach(func() {
+					broker.Spec.StaleClusterTimeout = nil
+				})
+
+				It("should still remove its broker resources", func(ctx SpecContext) {
+					// The ClusterJoinRequest created for the remaining cluster awaits its ServiceAccount token.
+					t.AssertReconcileRequeue(ctx)
+
+					assertClusterResources(ctx, "west", false)
+					assertClusterResources(ctx, "east", true)
+					Expect(getBroker(ctx).Status.StaleClusters).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("and a Lease's name doesn't match its cluster ID label", func() {
+			BeforeEach(func() {
+				forgedLease := newLease("west", time.Now().Add(-24*time.Hour))
+				forgedLease.Name = opnames.ForClusterHeartbeat("east")
+				forgedLease.Annotations = map[string]string{
+					"submariner.io/departed":    time.Now().Format(time.RFC3339),
+					"submariner.io/stale-since": time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+				}
+
+				eastLease.Name = "east-heartbeat"
+				eastLease.Annotations = map[string]string{
+					"submariner.io/departed": time.Now().Format(time.RFC3339),
+				}
+
+				t.InitScopedClientObjs = append(t.InitScopedClientObjs, forgedLease)
+			})
+
+			It("should ignore it", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				assertClusterResources(ctx, "west", true)
+				assertClusterResources(ctx, "east", true)
+				Expect(getBroker(ctx).Status.StaleClusters).To(BeEmpty())
+			})
+		})
+
+		Context("and a cluster has never renewed its heartbeat", func() {
+			BeforeEach(func() {
+				eastLease.Spec = coordinationv1.LeaseSpec{}
+				eastLease.CreationTimestamp = metav1.NewTime(time.Now().Add(-24 * time.Hour))
+			})
+
+			It("should not consider it stale", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				lease, err := getLease(ctx, eastLease)
+				Expect(err).To(Succeed())
+				Expect(lease.Annotations).ToNot(HaveKey("submariner.io/stale-since"))
+
+				assertClusterResources(ctx, "east", true)
+				Expect(getBroker(ctx).Status.StaleClusters).To(BeEmpty())
+			})
+		})
+
+		Context("and a stale cluster renews its heartbeat", func() {
+			BeforeEach(func() {
+				eastLease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now()))
+				eastLease.Annotations = map[string]string{
+					"submariner.io/stale-since": time.Now().Add(-time.Hour).Format(time.RFC3339),
+				}
+			})
+
+			It("should clear its stale mark", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				lease, err := getLease(ctx, eastLease)
+				Expect(err).To(Succeed())
+				Expect(lease.Annotations).ToNot(HaveKey("submariner.io/stale-since"))
+
+				assertClusterResources(ctx, "east", true)
+				Expect(getBroker(ctx).Status.StaleClusters).To(BeEmpty())
+			})
+		})
+	})
+
 	When("the Broker resource doesn't exist", func() {
 		BeforeEach(func() {
 			t.InitScopedClientObjs = nil
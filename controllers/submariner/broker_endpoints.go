@@ -0,0 +1,121 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	submopv1a1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
+)
+
+// When more than one broker endpoint is configured, they are re-checked at this interval so that an unreachable
+// active broker is noticed even if nothing else triggers a reconcile.
+const brokerEndpointHealthCheckInterval = 30 * time.Second
+
+// selectActiveBrokerEndpoint checks the configured broker endpoints in order and records the first one that can be
+// accessed as the active endpoint in the status. If none can be accessed, the previously active endpoint is retained.
+func (r *Reconciler) selectActiveBrokerEndpoint(ctx context.Context, instance *submopv1a1.Submariner, reqLogger logr.Logger) {
+	endpoints := brokerEndpoints(&instance.Spec)
+	if len(endpoints) <= 1 {
+		instance.Status.ActiveBrokerK8sApiServer = instance.Spec.BrokerK8sApiServer
+		if len(endpoints) == 1 {
+			instance.Status.ActiveBrokerK8sApiServer = endpoints[0].ApiServer
+		}
+
+		return
+	}
+
+	for i := range endpoints {
+		_, err := r.getBrokerClientFor(ctx, instance, endpoints[i].ApiServer)
+		if err != nil {
+			reqLogger.Error(err, "Broker endpoint is not available", "apiServer", endpoints[i].ApiServer)
+			continue
+		}
+
+		if instance.Status.ActiveBrokerK8sApiServer != endpoints[i].ApiServer {
+			reqLogger.Info("Switching the active broker endpoint", "previous", instance.Status.ActiveBrokerK8sApiServer,
+				"new", endpoints[i].ApiServer)
+		}
+
+		instance.Status.ActiveBrokerK8sApiServer = endpoints[i].ApiServer
+
+		return
+	}
+
+	if findBrokerEndpoint(&instance.Spec, instance.Status.ActiveBrokerK8sApiServer) == nil {
+		instance.Status.ActiveBrokerK8sApiServer = endpoints[0].ApiServer
+	}
+
+	reqLogger.Info("No broker endpoint is available - keeping the current one", "apiServer", instance.Status.ActiveBrokerK8sApiServer)
+}
+
+// brokerEndpoints returns the broker endpoints in order of preference: BrokerK8sApiServer with BrokerK8sCA, if set,
+// followed by the additional BrokerK8sEndpoints.
+func brokerEndpoints(spec *submopv1a1.SubmarinerSpec) []submopv1a1.BrokerK8sEndpoint {
+	endpoints := make([]submopv1a1.BrokerK8sEndpoint, 0, len(spec.BrokerK8sEndpoints)+1)
+
+	if spec.BrokerK8sApiServer != "" {
+		endpoints = append(endpoints, submopv1a1.BrokerK8sEndpoint{ApiServer: spec.BrokerK8sApiServer})
+	}
+
+	for i := range spec.BrokerK8sEndpoints {
+		if spec.BrokerK8sEndpoints[i].ApiServer != spec.BrokerK8sApiServer {
+			endpoints = append(endpoints, spec.BrokerK8sEndpoints[i])
+		}
+	}
+
+	return endpoints
+}
+
+// activeBrokerEndpoint returns the API server and CA of the broker endpoint currently in use, as selected by
+// selectActiveBrokerEndpoint.
+func activeBrokerEndpoint(instance *submopv1a1.Submariner) (string, string) {
+	endpoint := findBrokerEndpoint(&instance.Spec, instance.Status.ActiveBrokerK8sApiServer)
+	if endpoint == nil {
+		return instance.Spec.BrokerK8sApiServer, instance.Spec.BrokerK8sCA
+	}
+
+	if endpoint.CA == "" {
+		return endpoint.ApiServer, instance.Spec.BrokerK8sCA
+	}
+
+	return endpoint.ApiServer, endpoint.CA
+}
+
+func findBrokerEndpoint(spec *submopv1a1.SubmarinerSpec, apiServer string) *submopv1a1.BrokerK8sEndpoint {
+	endpoints := brokerEndpoints(spec)
+
+	for i := range endpoints {
+		if endpoints[i].ApiServer == apiServer {
+			return &endpoints[i]
+		}
+	}
+
+	return nil
+}
+
+func brokerHealthCheckInterval(instance *submopv1a1.Submariner) time.Duration {
+	if len(brokerEndpoints(&instance.Spec)) > 1 {
+		return brokerEndpointHealthCheckInterval
+	}
+
+	return 0
+}
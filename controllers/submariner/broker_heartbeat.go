@@ -0,0 +1,138 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	submopv1a1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+)
+
+// Member clusters renew a Lease on the broker at this interval, which allows the broker to detect clusters that were
+// removed without being uninstalled.
+const brokerHeartbeatInterval = time.Minute
+
+var leaseGVR = coordinationv1.SchemeGroupVersion.WithResource("leases")
+
+// brokerHeartbeatKey identifies the broker a heartbeat is renewed on, and the cluster it is renewed for.
+type brokerHeartbeatKey struct {
+	namespace       string
+	apiServer       string
+	remoteNamespace string
+	clusterID       string
+}
+
+func brokerHeartbeatKeyFor(instance *submopv1a1.Submariner) brokerHeartbeatKey {
+	apiServer, _ := activeBrokerEndpoint(instance)
+
+	return brokerHeartbeatKey{
+		namespace:       instance.Namespace,
+		apiServer:       apiServer,
+		remoteNamespace: instance.Spec.BrokerK8sRemoteNamespace,
+		clusterID:       instance.Spec.ClusterID,
+	}
+}
+
+// setupBrokerHeartbeat starts renewing the cluster's heartbeat on the broker, if the broker credentials are available.
+// The heartbeat is independent of the secret syncer: clusters configured with a broker token and no broker secret
+// renew it too. If the broker can't be accessed, this is retried on the next reconcile.
+func (r *Reconciler) setupBrokerHeartbeat(ctx context.Context, instance *submopv1a1.Submariner, logger logr.Logger) {
+	key := brokerHeartbeatKeyFor(instance)
+
+	if _, ok := r.brokerHeartbeats[key]; ok {
+		return
+	}
+
+	// Any other heartbeat for this namespace was started for a previous broker configuration.
+	r.cancelBrokerHeartbeatsFor(instance.Namespace, logger)
+
+	if instance.Spec.BrokerK8sRemoteNamespace == "" ||
+		(instance.Spec.BrokerK8sSecret == "" && instance.Spec.BrokerK8sApiServerToken == "") {
+		return
+	}
+
+	brokerClient, err := r.getBrokerClient(ctx, instance)
+	if err != nil {
+		logger.Error(err, "Unable to access the broker to renew the cluster's heartbeat")
+		return
+	}
+
+	heartbeatCtx, cancelFunc := context.WithCancel(context.TODO())
+
+	go runBrokerHeartbeat(heartbeatCtx, brokerClient, key.remoteNamespace, key.clusterID)
+
+	r.brokerHeartbeats[key] = cancelFunc
+
+	logger.Info("Started the broker heartbeat", "brokerAPIServer", key.apiServer, "brokerNamespace", key.remoteNamespace)
+}
+
+// cancelBrokerHeartbeatsFor stops renewing the heartbeats started for the Submariner in the given namespace.
+func (r *Reconciler) cancelBrokerHeartbeatsFor(namespace string, logger logr.Logger) {
+	for key, cancel := range r.brokerHeartbeats {
+		if key.namespace != namespace {
+			continue
+		}
+
+		logger.Info("Stopping the broker heartbeat", "brokerAPIServer", key.apiServer, "brokerNamespace", key.remoteNamespace)
+
+		cancel()
+		delete(r.brokerHeartbeats, key)
+	}
+}
+
+// runBrokerHeartbeat renews the cluster's heartbeat Lease on the broker until the context is cancelled.
+func runBrokerHeartbeat(ctx context.Context, brokerClient dynamic.Interface, brokerNamespace, clusterID string) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := renewBrokerHeartbeat(ctx, brokerClient, brokerNamespace, clusterID); err != nil && ctx.Err() == nil {
+			log.Error(err, "Error renewing the heartbeat on the broker", "brokerNamespace", brokerNamespace)
+		}
+	}, brokerHeartbeatInterval)
+}
+
+// renewBrokerHeartbeat renews the cluster's heartbeat Lease. The Lease is created by the broker, members are only allowed
+// to update their own. Only the holder and renewal time are patched, so that the marks set on the Lease, by the broker
+// or when the cluster leaves, are preserved.
+func renewBrokerHeartbeat(ctx context.Context, brokerClient dynamic.Interface, brokerNamespace, clusterID string) error {
+	patch, err := json.Marshal(map[string]any{
+		"spec": coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(clusterID),
+			LeaseDurationSeconds: ptr.To(int32(brokerHeartbeatInterval.Seconds())),
+			RenewTime:            ptr.To(metav1.NewMicroTime(time.Now())),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the heartbeat patch")
+	}
+
+	_, err = brokerClient.Resource(leaseGVR).Namespace(brokerNamespace).Patch(ctx, names.ForClusterHeartbeat(clusterID),
+		types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return errors.Wrap(err, "error patching the heartbeat Lease")
+}
@@ -0,0 +1,317 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/embeddedyamls"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;delete
+//nolint:lll // Markers can't be wrapped
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,resourceNames=submariner-k8s-broker-admin;submariner-k8s-broker-cluster,verbs=bind;escalate
+
+// The broker resources each component needs access to; rules which only cover resources of disabled components are
+// left out of the broker Roles.
+var brokerComponentResources = map[string][]string{
+	v1alpha1.ComponentConnectivity:     {"clusters", "endpoints"},
+	v1alpha1.ComponentServiceDiscovery: {"serviceimports", "serviceimports/status", "endpointslices", "endpointslices/restricted"},
+}
+
+// reconcileBrokerRBAC creates or updates the broker admin and client ServiceAccounts, Roles and RoleBindings from the
+// embedded manifests. They're owned by the Broker so that they're removed along with it.
+func (r *BrokerReconciler) reconcileBrokerRBAC(ctx context.Context, broker *v1alpha1.Broker) error {
+	for _, yamls := range [][3]string{
+		{
+			embeddedyamls.Config_broker_broker_admin_service_account_yaml,
+			embeddedyamls.Config_broker_broker_admin_role_yaml,
+			embeddedyamls.Config_broker_broker_admin_role_binding_yaml,
+		},
+		{
+			embeddedyamls.Config_broker_broker_client_service_account_yaml,
+			embeddedyamls.Config_broker_broker_client_role_yaml,
+			embeddedyamls.Config_broker_broker_client_role_binding_yaml,
+		},
+	} {
+		if err := r.reconcileBrokerServiceAccount(ctx, broker, yamls[0], yamls[1], yamls[2]); err != nil {
+			return err
+		}
+	}
+
+	return r.reconcileClusterRBAC(ctx, broker)
+}
+
+func (r *BrokerReconciler) reconcileBrokerServiceAccount(ctx context.Context, broker *v1alpha1.Broker,
+	saYAML, roleYAML, roleBindingYAML string,
+) error {
+	sa := &corev1.ServiceAccount{}
+	if err := embeddedyamls.GetObject(saYAML, sa); err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	if err := r.createOrUpdateForBroker(ctx, broker, sa, func() {}); err != nil {
+		return err
+	}
+
+	expectedRole := &rbacv1.Role{}
+	if err := embeddedyamls.GetObject(roleYAML, expectedRole); err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	role := &rbacv1.Role{ObjectMeta: expectedRole.ObjectMeta}
+
+	err := r.createOrUpdateForBroker(ctx, broker, role, func() {
+		role.Rules = brokerRoleRules(broker, expectedRole.Rules)
+	})
+	if err != nil {
+		return err
+	}
+
+	expectedRoleBinding := &rbacv1.RoleBinding{}
+	if err := embeddedyamls.GetObject(roleBindingYAML, expectedRoleBinding); err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: expectedRoleBinding.ObjectMeta}
+
+	return r.createOrUpdateForBroker(ctx, broker, roleBinding, func() {
+		roleBinding.RoleRef = expectedRoleBinding.RoleRef
+		roleBinding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      sa.Name,
+			Namespace: broker.Namespace,
+		}}
+	})
+}
+
+// reconcileClusterRBAC grants the broker ServiceAccount of each member cluster, whether it was created for a
+// ClusterJoinRequest or out of band by subctl, access to the broker resources which belong to that cluster alone.
+func (r *BrokerReconciler) reconcileClusterRBAC(ctx context.Context, broker *v1alpha1.Broker) error {
+	serviceAccounts := &corev1.ServiceAccountList{}
+
+	err := r.Client.List(ctx, serviceAccounts, client.InNamespace(broker.Namespace))
+	if err != nil {
+		return errors.Wrap(err, "error listing ServiceAccounts")
+	}
+
+	for i := range serviceAccounts.Items {
+		clusterID, ok := names.ClusterIDForSA(serviceAccounts.Items[i].Name)
+		if !ok || serviceAccounts.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		if err := r.ensureClusterRole(ctx, &serviceAccounts.Items[i], clusterID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureClusterRole allows the given member cluster ServiceAccount to access the broker resources which belong to the
+// cluster alone, and only those: its heartbeat Lease, which is created here since creation can't be restricted to
+// specific names, its ClusterJoinRequests, through which it requests its CIDRs from the broker, and its CIDRAllocations,
+// which it deletes to release its CIDRs when it leaves. The broker client Role only allows reading ClusterJoinRequests
+// and CIDRAllocations, and grants no access to Leases, so that members can't allocate CIDRs on behalf of other
+// clusters, nor mark each other as departed or stale. The Role and its RoleBinding are owned by the ServiceAccount so
+// that they're removed along with it.
+func (r *BrokerReconciler) ensureClusterRole(ctx context.Context, sa *corev1.ServiceAccount, clusterID string) error {
+	if err := r.ensureClusterHeartbeat(ctx, sa.Namespace, clusterID); err != nil {
+		return err
+	}
+
+	joinRequestNames, err := r.ensureClusterJoinRequest(ctx, sa.Namespace, clusterID)
+	if err != nil {
+		return err
+	}
+
+	roleName := names.ForClusterRole(clusterID)
+
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: sa.Namespace}}
+
+	err = r.createOrUpdateForClusterSA(ctx, sa, clusterID, role, func() {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{coordinationv1.GroupName},
+				Resources:     []string{"leases"},
+				ResourceNames: []string{names.ForClusterHeartbeat(clusterID)},
+				Verbs:         []string{"get", "update"},
+			},
+			{
+				APIGroups:     []string{v1alpha1.GroupVersion.Group},
+				Resources:     []string{"clusterjoinrequests"},
+				ResourceNames: joinRequestNames,
+				Verbs:         []string{"get", "update"},
+			},
+			{
+				APIGroups: []string{v1alpha1.GroupVersion.Group},
+				Resources: []string{"cidrallocations"},
+				ResourceNames: []string{
+					names.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolGlobalnet, clusterID),
+					names.ForCIDRAllocation(v1alpha1.CIDRAllocationPoolClustersetIP, clusterID),
+				},
+				Verbs: []string{"delete"},
+			},
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: sa.Namespace}}
+
+	return r.createOrUpdateForClusterSA(ctx, sa, clusterID, roleBinding, func() {
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     roleName,
+		}
+		roleBinding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
+		}}
+	})
+}
+
+// brokerRoleRules returns the given rules, without those which only grant access to the resources of components which
+// aren't enabled on the broker.
+func brokerRoleRules(broker *v1alpha1.Broker, rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	var disabledResources []string
+
+	for component, resources := range brokerComponentResources {
+		if !brokerHasComponent(broker, component) {
+			disabledResources = append(disabledResources, resources...)
+		}
+	}
+
+	return slices.DeleteFunc(rules, func(rule rbacv1.PolicyRule) bool {
+		return len(rule.Resources) > 0 && !slices.ContainsFunc(rule.Resources, func(resource string) bool {
+			return !slices.Contains(disabledResources, resource)
+		})
+	})
+}
+
+// createOrUpdateForBroker creates or updates the given object in the broker's namespace, owned by the broker.
+func (r *BrokerReconciler) createOrUpdateForBroker(ctx context.Context, broker *v1alpha1.Broker, obj client.Object,
+	mutate func(),
+) error {
+	obj.SetNamespace(broker.Namespace)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		mutate()
+
+		return controllerutil.SetControllerReference(broker, obj, r.Client.Scheme())
+	})
+
+	return errors.Wrapf(err, "error creating or updating %T %q", obj, obj.GetName())
+}
+
+// ensureClusterHeartbeat creates the member cluster's heartbeat Lease if it doesn't exist. An existing Lease is left as
+// is, it's maintained by the cluster and the stale cluster check. The Lease isn't owned by the ServiceAccount, so that it
+// outlives it until the cluster's broker resources have all been removed.
+func (r *BrokerReconciler) ensureClusterHeartbeat(ctx context.Context, namespace, clusterID string) error {
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+		Name:      names.ForClusterHeartbeat(clusterID),
+		Namespace: namespace,
+		Labels:    map[string]string{federate.ClusterIDLabelKey: clusterID},
+	}}
+
+	err := r.Client.Create(ctx, lease)
+	if err == nil || apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+
+	return errors.Wrapf(err, "error creating the heartbeat Lease of cluster %q", clusterID)
+}
+
+// ensureClusterJoinRequest creates a ClusterJoinRequest for the member cluster if it doesn't have one, so that the CIDRs of
+// clusters whose ServiceAccount was created out of band by subctl are allocated by the broker too. It returns the names
+// of the cluster's requests.
+func (r *BrokerReconciler) ensureClusterJoinRequest(ctx context.Context, namespace, clusterID string) ([]string, error) {
+	requests := &v1alpha1.ClusterJoinRequestList{}
+
+	err := r.Client.List(ctx, requests, client.InNamespace(namespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing ClusterJoinRequests")
+	}
+
+	var requestNames []string
+
+	for i := range requests.Items {
+		if requests.Items[i].Spec.ClusterID == clusterID {
+			requestNames = append(requestNames, requests.Items[i].Name)
+		}
+	}
+
+	if len(requestNames) > 0 {
+		return requestNames, nil
+	}
+
+	request := &v1alpha1.ClusterJoinRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.ForClusterJoinRequest(clusterID),
+			Namespace: namespace,
+			Labels:    map[string]string{federate.ClusterIDLabelKey: clusterID},
+		},
+		Spec: v1alpha1.ClusterJoinRequestSpec{ClusterID: clusterID},
+	}
+
+	err = r.Client.Create(ctx, request)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "error creating the ClusterJoinRequest of cluster %q", clusterID)
+	}
+
+	return []string{request.Name}, nil
+}
+
+// createOrUpdateForClusterSA creates or updates the given object, labelled with the member cluster's ID and owned by the
+// cluster's ServiceAccount.
+func (r *BrokerReconciler) createOrUpdateForClusterSA(ctx context.Context, sa *corev1.ServiceAccount, clusterID string,
+	obj client.Object, mutate func(),
+) error {
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		mutate()
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		labels[federate.ClusterIDLabelKey] = clusterID
+		obj.SetLabels(labels)
+
+		return controllerutil.SetOwnerReference(sa, obj, r.Client.Scheme())
+	})
+
+	return errors.Wrapf(err, "error creating or updating %T %q", obj, obj.GetName())
+}
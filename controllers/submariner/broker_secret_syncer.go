@@ -0,0 +1,238 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	level "github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	submopv1a1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// brokerSecretSyncerKey identifies the broker a secret syncer is connected to, and the local secret it maintains.
+type brokerSecretSyncerKey struct {
+	namespace       string
+	secretName      string
+	apiServer       string
+	remoteNamespace string
+	clusterID       string
+}
+
+type brokerSecretSyncer struct {
+	// The Submariner resource the syncer was started for, which identifies its metrics.
+	namespace    string
+	name         string
+	cancel       context.CancelFunc
+	mutex        sync.Mutex
+	startTime    metav1.Time
+	lastSyncTime *metav1.Time
+	lastError    error
+}
+
+// statusRecordingFederator wraps a Federator to record the outcome of each sync operation in the owning syncer.
+type statusRecordingFederator struct {
+	federate.Federator
+	syncer *brokerSecretSyncer
+}
+
+func brokerSecretSyncerKeyFor(instance *submopv1a1.Submariner) brokerSecretSyncerKey {
+	apiServer, _ := activeBrokerEndpoint(instance)
+
+	return brokerSecretSyncerKey{
+		namespace:       instance.Namespace,
+		secretName:      instance.Spec.BrokerK8sSecret,
+		apiServer:       apiServer,
+		remoteNamespace: instance.Spec.BrokerK8sRemoteNamespace,
+		clusterID:       instance.Spec.ClusterID,
+	}
+}
+
+func (r *Reconciler) setupSecretSyncer(ctx context.Context, instance *submopv1a1.Submariner, logger logr.Logger, namespace string) error {
+	r.syncerMutex.Lock()
+	defer r.syncerMutex.Unlock()
+
+	key := brokerSecretSyncerKeyFor(instance)
+
+	if _, ok := r.secretSyncers[key]; ok {
+		return nil
+	}
+
+	// Any other syncer for this namespace was started for a previous broker configuration.
+	r.cancelSecretSyncersFor(instance.Namespace, logger)
+
+	if instance.Spec.BrokerK8sSecret == "" {
+		return nil
+	}
+
+	brokerClient, err := r.getBrokerClient(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	clusterID := instance.Spec.ClusterID
+	transformedSecretName := instance.Spec.BrokerK8sSecret
+	secretSyncer := &brokerSecretSyncer{namespace: instance.Namespace, name: instance.Name}
+
+	resourceSyncer, err := syncer.NewResourceSyncer(
+		&syncer.ResourceSyncerConfig{
+			Name:            "Broker secret syncer",
+			ResourceType:    &corev1.Secret{},
+			SourceClient:    brokerClient,
+			SourceNamespace: instance.Spec.BrokerK8sRemoteNamespace,
+			Direction:       syncer.None,
+			RestMapper:      r.config.ScopedClient.RESTMapper(),
+			Scheme:          r.config.Scheme,
+			Federator: &statusRecordingFederator{
+				Federator: federate.NewCreateOrUpdateFederator(
+					r.config.DynClient, r.config.ScopedClient.RESTMapper(), namespace, ""),
+				syncer: secretSyncer,
+			},
+			Transform: func(from runtime.Object, _ int, _ syncer.Operation) (runtime.Object, bool) {
+				secret := from.(*corev1.Secret)
+				logger.V(level.TRACE).Info("Transforming secret", "secret", secret)
+				if saName, ok := secret.ObjectMeta.Annotations[corev1.ServiceAccountNameKey]; ok &&
+					saName == names.ForClusterSA(clusterID) {
+					transformedSecret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: transformedSecretName,
+						},
+						Type: corev1.SecretTypeOpaque,
+						Data: secret.Data,
+					}
+					logger.V(level.TRACE).Info("Transformed secret", "transformedSecret", transformedSecret)
+					return transformedSecret, false
+				}
+				return nil, false
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error building a resource syncer for secrets")
+	}
+
+	syncerCtx, cancelFunc := context.WithCancel(context.TODO())
+	if err := resourceSyncer.Start(syncerCtx.Done()); err != nil {
+		cancelFunc()
+		return errors.Wrap(err, "error starting the secret syncer")
+	}
+
+	secretSyncer.cancel = cancelFunc
+	secretSyncer.startTime = metav1.Now().Rfc3339Copy()
+	r.secretSyncers[key] = secretSyncer
+
+	recordBrokerSecretSyncerStarted(secretSyncer.namespace, secretSyncer.name, secretSyncer.startTime.Time)
+
+	logger.Info("Started the broker secret syncer", "brokerAPIServer", key.apiServer,
+		"brokerNamespace", key.remoteNamespace, "secret", key.secretName)
+
+	return nil
+}
+
+func (r *Reconciler) cancelSecretSyncer(instance *submopv1a1.Submariner) {
+	r.syncerMutex.Lock()
+	defer r.syncerMutex.Unlock()
+
+	r.cancelSecretSyncersFor(instance.Namespace, log)
+}
+
+// cancelSecretSyncersFor cancels all the secret syncers maintaining secrets in the given namespace. The syncerMutex must be held.
+func (r *Reconciler) cancelSecretSyncersFor(namespace string, logger logr.Logger) {
+	for key, secretSyncer := range r.secretSyncers {
+		if key.namespace != namespace {
+			continue
+		}
+
+		logger.Info("Stopping the broker secret syncer", "brokerAPIServer", key.apiServer,
+			"brokerNamespace", key.remoteNamespace, "secret", key.secretName)
+
+		secretSyncer.cancel()
+		delete(r.secretSyncers, key)
+		recordBrokerSecretSyncerStopped(secretSyncer.namespace, secretSyncer.name)
+	}
+}
+
+func (r *Reconciler) secretSyncerStatus(instance *submopv1a1.Submariner) *submopv1a1.BrokerSecretSyncerStatus {
+	r.syncerMutex.Lock()
+	defer r.syncerMutex.Unlock()
+
+	key := brokerSecretSyncerKeyFor(instance)
+
+	secretSyncer, ok := r.secretSyncers[key]
+	if !ok {
+		return nil
+	}
+
+	secretSyncer.mutex.Lock()
+	defer secretSyncer.mutex.Unlock()
+
+	startTime := secretSyncer.startTime
+
+	status := &submopv1a1.BrokerSecretSyncerStatus{
+		BrokerK8sApiServer:       key.apiServer,
+		BrokerK8sRemoteNamespace: key.remoteNamespace,
+		StartTime:                &startTime,
+		LastSyncTime:             secretSyncer.lastSyncTime.DeepCopy(),
+	}
+
+	if secretSyncer.lastError != nil {
+		status.LastError = secretSyncer.lastError.Error()
+	}
+
+	return status
+}
+
+func (s *brokerSecretSyncer) recordSync(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := metav1.Now().Rfc3339Copy()
+
+	if err != nil {
+		s.lastError = err
+		recordBrokerSecretSyncerError(s.namespace, s.name, now.Time)
+
+		return
+	}
+
+	s.lastError = nil
+	s.lastSyncTime = &now
+	recordBrokerSecretSyncerSync(s.namespace, s.name, now.Time)
+}
+
+func (f *statusRecordingFederator) Distribute(ctx context.Context, obj runtime.Object) error {
+	err := f.Federator.Distribute(ctx, obj)
+	f.syncer.recordSync(err)
+
+	return err //nolint:wrapcheck // No need to wrap here
+}
+
+func (f *statusRecordingFederator) Delete(ctx context.Context, obj runtime.Object) error {
+	err := f.Federator.Delete(ctx, obj)
+	f.syncer.recordSync(err)
+
+	return err //nolint:wrapcheck // No need to wrap here
+}
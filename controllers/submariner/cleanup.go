@@ -27,12 +27,15 @@ import (
 	"github.com/submariner-io/admiral/pkg/resource"
 	operatorv1alpha1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
 	"github.com/submariner-io/submariner-operator/controllers/uninstall"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
 	"github.com/submariner-io/submariner-operator/pkg/images"
 	opnames "github.com/submariner-io/submariner-operator/pkg/names"
 	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -43,6 +46,8 @@ func (r *Reconciler) runComponentCleanup(ctx context.Context, instance *operator
 
 	if !uninstall.IsSupportedForVersion(instance.Spec.Version) {
 		log.Info("Deleting Submariner version does not support uninstall", "version", instance.Spec.Version)
+		r.leaveBroker(ctx, instance)
+
 		return reconcile.Result{}, r.removeFinalizer(ctx, instance)
 	}
 
@@ -52,6 +57,9 @@ func (r *Reconciler) runComponentCleanup(ctx context.Context, instance *operator
 		return reconcile.Result{}, err
 	}
 
+	// CIDRs allocated from the broker are already recorded in the status.
+	recordSpecifiedCIDRs(instance)
+
 	components := []*uninstall.Component{
 		{
 			Resource:          newDaemonSet(names.GatewayComponent, instance.Namespace),
@@ -65,7 +73,7 @@ func (r *Reconciler) runComponentCleanup(ctx context.Context, instance *operator
 			Resource:          newDaemonSet(names.GlobalnetComponent, instance.Namespace),
 			UninstallResource: newGlobalnetDaemonSet(instance, opnames.AppendUninstall(names.GlobalnetComponent)),
 			CheckInstalled: func() bool {
-				return instance.Spec.GlobalCIDR != ""
+				return instance.Status.GlobalCIDR != ""
 			},
 		},
 	}
@@ -94,9 +102,51 @@ func (r *Reconciler) runComponentCleanup(ctx context.Context, instance *operator
 		return reconcile.Result{RequeueAfter: time.Millisecond * 500}, nil
 	}
 
+	r.leaveBroker(ctx, instance)
+
 	return reconcile.Result{}, r.removeFinalizer(ctx, instance)
 }
 
+// leaveBroker releases the cluster's globalnet and clustersetip CIDRs on the broker, and marks the cluster's heartbeat
+// Lease as departed so that the broker removes the cluster's other resources, including any allocations still recorded
+// in the pool ConfigMaps, which member clusters can't update. This is best effort: the broker may no
+// longer be reachable, and its stale cluster cleanup takes care of the cluster eventually anyway.
+func (r *Reconciler) leaveBroker(ctx context.Context, instance *operatorv1alpha1.Submariner) {
+	defer delete(r.brokerControllerClients, instance.Namespace)
+
+	brokerClient, err := r.getBrokerControllerClient(ctx, instance)
+	if err != nil {
+		log.Error(err, "Unable to access the broker to announce the cluster's departure")
+		return
+	}
+
+	namespace := instance.Spec.BrokerK8sRemoteNamespace
+
+	for _, pool := range []string{operatorv1alpha1.CIDRAllocationPoolGlobalnet, operatorv1alpha1.CIDRAllocationPoolClustersetIP} {
+		if _, err := cidr.RemoveAllocation(ctx, brokerClient, namespace, pool, instance.Spec.ClusterID); err != nil {
+			log.Error(err, "Error releasing the cluster's CIDRs on the broker", "pool", pool)
+		}
+	}
+
+	// The Lease is created by the broker; brokers which predate it don't track the cluster's heartbeat.
+	lease := &coordinationv1.Lease{}
+
+	err = brokerClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: opnames.ForClusterHeartbeat(instance.Spec.ClusterID)},
+		lease)
+	if apierrors.IsNotFound(err) {
+		return
+	}
+
+	if err == nil {
+		metav1.SetMetaDataAnnotation(&lease.ObjectMeta, departedAnnotation, time.Now().Format(time.RFC3339))
+		err = brokerClient.Update(ctx, lease)
+	}
+
+	if err != nil {
+		log.Error(err, "Error announcing the cluster's departure to the broker")
+	}
+}
+
 func (r *Reconciler) removeFinalizer(ctx context.Context, instance *operatorv1alpha1.Submariner) error {
 	return finalizer.Remove[*operatorv1alpha1.Submariner](ctx, resource.ForControllerClient(
 		r.config.ScopedClient, instance.Namespace, &operatorv1alpha1.Submariner{}),
@@ -0,0 +1,397 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/admiral/pkg/reporter"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	"github.com/submariner-io/submariner-operator/pkg/embeddedyamls"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// The IPsec PSK shared by all the clusters, as created on the broker by subctl.
+	ipsecPSKSecretName = "submariner-ipsec-psk"
+
+	// Join requests are re-checked at this interval until their join bundle is complete; in particular, the cluster's
+	// ServiceAccount token is populated asynchronously.
+	joinRequestRetryInterval = 5 * time.Second
+)
+
+// Reasons of the ClusterJoinRequest Ready condition. Member clusters rely on them to tell whether their CIDRs have been
+// allocated.
+const (
+	joinRequestInvalidSpec          = "InvalidSpec"
+	joinRequestServiceAccountFailed = "ServiceAccountFailed"
+	joinRequestAllocationFailed     = "AllocationFailed"
+	joinRequestAwaitingToken        = "AwaitingToken"
+	joinRequestJoinBundleFailed     = "JoinBundleFailed"
+	joinRequestJoinBundlePublished  = "JoinBundlePublished"
+)
+
+//+kubebuilder:rbac:groups=submariner.io,resources=clusterjoinrequests,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=submariner.io,resources=clusterjoinrequests/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=serviceaccounts;secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;delete
+
+// reconcileClusterJoinRequests processes all the ClusterJoinRequests in the broker's namespace, and returns true if any
+// of them is still awaiting completion. A request which fails doesn't hold up the others; the errors are aggregated.
+func (r *BrokerReconciler) reconcileClusterJoinRequests(ctx context.Context, broker *v1alpha1.Broker) (bool, error) {
+	requests := &v1alpha1.ClusterJoinRequestList{}
+
+	err := r.Client.List(ctx, requests, client.InNamespace(broker.Namespace))
+	if err != nil {
+		return false, errors.Wrap(err, "error listing ClusterJoinRequests")
+	}
+
+	pending := false
+
+	var errs []error
+
+	for i := range requests.Items {
+		if requests.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		ready, err := r.reconcileClusterJoinRequest(ctx, broker, &requests.Items[i])
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		pending = pending || !ready
+	}
+
+	return pending, k8serrors.NewAggregate(errs) //nolint:wrapcheck // The individual errors are already wrapped.
+}
+
+func (r *BrokerReconciler) reconcileClusterJoinRequest(ctx context.Context, broker *v1alpha1.Broker,
+	request *v1alpha1.ClusterJoinRequest,
+) (bool, error) {
+	initialStatus := request.Status.DeepCopy()
+
+	ready, reason, err := r.processClusterJoinRequest(ctx, broker, request)
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ClusterJoinRequestReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		ObservedGeneration: request.Generation,
+	}
+
+	switch {
+	case err != nil:
+		condition.Message = err.Error()
+	case ready:
+		condition.Status = metav1.ConditionTrue
+		condition.Message = "The join bundle is available in Secret " + request.Status.JoinBundleSecret
+	default:
+		condition.Message = "Waiting for the token of ServiceAccount " + request.Status.ServiceAccount
+	}
+
+	meta.SetStatusCondition(&request.Status.Conditions, condition)
+
+	if !equality.Semantic.DeepEqual(initialStatus, &request.Status) {
+		if updateErr := r.Client.Status().Update(ctx, request); updateErr != nil && err == nil {
+			err = errors.Wrapf(updateErr, "error updating the status of ClusterJoinRequest %q", request.Name)
+		}
+	}
+
+	return ready, err
+}
+
+// processClusterJoinRequest creates the cluster's broker resources and join bundle, updating the request's status as it
+// goes. It returns whether the join bundle is complete, and a reason suitable for the request's Ready condition.
+func (r *BrokerReconciler) processClusterJoinRequest(ctx context.Context, broker *v1alpha1.Broker,
+	request *v1alpha1.ClusterJoinRequest,
+) (bool, string, error) {
+	if request.Spec.ClusterID == "" {
+		return false, joinRequestInvalidSpec, errors.Errorf("ClusterJoinRequest %q has no cluster ID", request.Name)
+	}
+
+	tokenSecret, err := r.ensureClusterServiceAccount(ctx, request)
+	if err != nil {
+		return false, joinRequestServiceAccountFailed, err
+	}
+
+	err = r.allocateJoiningClusterCIDRs(ctx, broker, request)
+	if err != nil {
+		return false, joinRequestAllocationFailed, err
+	}
+
+	if len(tokenSecret.Data[corev1.ServiceAccountTokenKey]) == 0 {
+		return false, joinRequestAwaitingToken, nil
+	}
+
+	err = r.ensureJoinBundle(ctx, broker, request, tokenSecret)
+	if err != nil {
+		return false, joinRequestJoinBundleFailed, err
+	}
+
+	return true, joinRequestJoinBundlePublished, nil
+}
+
+// ensureClusterServiceAccount creates the joining cluster's broker ServiceAccount, its token Secret and its RoleBindings,
+// based on the broker client manifests. It returns the current token Secret.
+func (r *BrokerReconciler) ensureClusterServiceAccount(ctx context.Context, request *v1alpha1.ClusterJoinRequest,
+) (*corev1.Secret, error) {
+	saName := names.ForClusterSA(request.Spec.ClusterID)
+
+	sa := &corev1.ServiceAccount{}
+	if err := embeddedyamls.GetObject(embeddedyamls.Config_broker_broker_client_service_account_yaml, sa); err != nil {
+		return nil, err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	sa.ObjectMeta = metav1.ObjectMeta{Name: saName, Namespace: request.Namespace}
+
+	if err := r.createOrUpdateForJoinRequest(ctx, request, sa, func() {}); err != nil {
+		return nil, err
+	}
+
+	request.Status.ServiceAccount = saName
+
+	roleBinding := &rbacv1.RoleBinding{}
+	if err := embeddedyamls.GetObject(embeddedyamls.Config_broker_broker_client_role_binding_yaml, roleBinding); err != nil {
+		return nil, err //nolint:wrapcheck // Errors are already wrapped
+	}
+
+	roleRef := roleBinding.RoleRef
+	roleBinding.ObjectMeta = metav1.ObjectMeta{Name: saName, Namespace: request.Namespace}
+
+	err := r.createOrUpdateForJoinRequest(ctx, request, roleBinding, func() {
+		roleBinding.RoleRef = roleRef
+		roleBinding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      saName,
+			Namespace: request.Namespace,
+		}}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.ensureClusterRole(ctx, sa, request.Spec.ClusterID); err != nil {
+		return nil, err
+	}
+
+	// Since Kubernetes 1.24, ServiceAccount token Secrets are no longer created automatically.
+	tokenSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: saName + "-token", Namespace: request.Namespace}}
+
+	err = r.createOrUpdateForJoinRequest(ctx, request, tokenSecret, func() {
+		tokenSecret.Type = corev1.SecretTypeServiceAccountToken
+
+		if tokenSecret.Annotations == nil {
+			tokenSecret.Annotations = map[string]string{}
+		}
+
+		tokenSecret.Annotations[corev1.ServiceAccountNameKey] = saName
+	})
+
+	return tokenSecret, err
+}
+
+func (r *BrokerReconciler) allocateJoiningClusterCIDRs(ctx context.Context, broker *v1alpha1.Broker,
+	request *v1alpha1.ClusterJoinRequest,
+) error {
+	status := reporter.Klog()
+
+	if brokerHasComponent(broker, v1alpha1.ComponentConnectivity) {
+		if err := r.prepareGlobalCIDRAllocation(ctx, request); err != nil {
+			return err
+		}
+
+		globalnetConfig := &globalnet.Config{
+			ClusterID:   request.Spec.ClusterID,
+			GlobalCIDR:  request.Spec.GlobalCIDR,
+			ClusterSize: request.Spec.GlobalnetClusterSize,
+			Blocks:      request.Spec.GlobalCIDRBlocks,
+		}
+
+		err := globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, r.AllocationClient, request.Namespace, globalnetConfig, status)
+		if err != nil {
+			return errors.Wrap(err, "error allocating the global CIDR")
+		}
+
+		request.Status.GlobalCIDR = globalnetConfig.GlobalCIDR
+		request.Status.AdditionalGlobalCIDRs = globalnetConfig.AdditionalGlobalCIDRs
+	}
+
+	if brokerHasComponent(broker, v1alpha1.ComponentServiceDiscovery) {
+		// ClustersetIP CIDRs can only be allocated if the broker has a ClustersetIP CIDR range, which is optional.
+		clustersetIPInfo, _, err := clustersetip.GetClustersetIPNetworks(ctx, r.AllocationClient, request.Namespace)
+		if err != nil {
+			return errors.Wrap(err, "error retrieving the ClustersetIP information")
+		}
+
+		if clustersetIPInfo.CIDR == "" && request.Spec.ClustersetIPCIDR == "" {
+			return nil
+		}
+
+		clustersetIPConfig := &clustersetip.Config{
+			ClusterID:        request.Spec.ClusterID,
+			ClustersetIPCIDR: request.Spec.ClustersetIPCIDR,
+		}
+
+		_, err = clustersetip.AllocateCIDRFromConfigMap(ctx, r.AllocationClient, request.Namespace, clustersetIPConfig, status)
+		if err != nil {
+			return errors.Wrap(err, "error allocating the ClustersetIP CIDR")
+		}
+
+		request.Status.ClustersetIPCIDR = clustersetIPConfig.ClustersetIPCIDR
+	}
+
+	return nil
+}
+
+// prepareGlobalCIDRAllocation checks the cluster's requested global CIDR against the globalnet pool, and releases its
+// global CIDRs if they aren't of the size it requested, as granted by the broker, so that they're reallocated with that
+// size. The broker grants requested sizes rounded up to a power of 2; sizes which can't be allocated from the pool are
+// rejected when allocating.
+func (r *BrokerReconciler) prepareGlobalCIDRAllocation(ctx context.Context, request *v1alpha1.ClusterJoinRequest) error {
+	globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, r.AllocationClient, request.Namespace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error retrieving the globalnet pool")
+	}
+
+	if !globalnetInfo.Enabled {
+		return nil
+	}
+
+	if request.Spec.GlobalCIDR != "" {
+		return errors.Wrap(cidr.ValidateAllocation(globalnetInfo.CIDR, request.Spec.GlobalCIDR),
+			"the requested global CIDR can't be allocated from the globalnet pool")
+	}
+
+	existing := globalnetInfo.Clusters[request.Spec.ClusterID]
+	if request.Spec.GlobalnetClusterSize == 0 || existing == nil || len(existing.CIDRs) == 0 {
+		return nil
+	}
+
+	granted, err := cidr.GetValidAllocationSize(globalnetInfo.CIDR, cidr.AllocationSize(request.Spec.GlobalnetClusterSize))
+	if err != nil {
+		return nil //nolint:nilerr // The size is rejected when allocating
+	}
+
+	size, err := cidr.Size(existing.CIDRs[0])
+	if err == nil && size.Cmp(granted) == 0 {
+		return nil
+	}
+
+	log.Info("Releasing the global CIDRs of member cluster to reallocate them with the requested size",
+		"clusterID", request.Spec.ClusterID, "CIDRs", existing.CIDRs, "size", granted)
+
+	return globalnet.Release(ctx, r.AllocationClient, request.Namespace, request.Spec.ClusterID) //nolint:wrapcheck // Already wrapped
+}
+
+func (r *BrokerReconciler) ensureJoinBundle(ctx context.Context, broker *v1alpha1.Broker, request *v1alpha1.ClusterJoinRequest,
+	tokenSecret *corev1.Secret,
+) error {
+	apiServer := broker.Spec.BrokerK8sApiServer
+	if apiServer == "" && r.Config != nil {
+		apiServer = r.Config.Host
+	}
+
+	data := map[string][]byte{
+		v1alpha1.JoinBundleBrokerK8sApiServer:       []byte(apiServer),
+		v1alpha1.JoinBundleBrokerK8sApiServerToken:  tokenSecret.Data[corev1.ServiceAccountTokenKey],
+		v1alpha1.JoinBundleBrokerK8sCA:              []byte(base64.StdEncoding.EncodeToString(tokenSecret.Data[corev1.ServiceAccountRootCAKey])),
+		v1alpha1.JoinBundleBrokerK8sRemoteNamespace: []byte(request.Namespace),
+		v1alpha1.JoinBundleClusterID:                []byte(request.Spec.ClusterID),
+		v1alpha1.JoinBundleGlobalCIDR:               []byte(request.Status.GlobalCIDR),
+		v1alpha1.JoinBundleAdditionalGlobalCIDRs:    []byte(strings.Join(request.Status.AdditionalGlobalCIDRs, ",")),
+		v1alpha1.JoinBundleClustersetIPCIDR:         []byte(request.Status.ClustersetIPCIDR),
+		v1alpha1.JoinBundleClustersetIPEnabled:      []byte(strconv.FormatBool(broker.Spec.ClustersetIPEnabled)),
+		v1alpha1.JoinBundleServiceDiscoveryEnabled: []byte(strconv.FormatBool(
+			brokerHasComponent(broker, v1alpha1.ComponentServiceDiscovery))),
+		v1alpha1.JoinBundleCustomDomains: []byte(strings.Join(broker.Spec.DefaultCustomDomains, ",")),
+	}
+
+	if brokerHasComponent(broker, v1alpha1.ComponentConnectivity) {
+		pskSecret := &corev1.Secret{}
+
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: ipsecPSKSecretName}, pskSecret)
+		if err == nil {
+			data[v1alpha1.JoinBundleCeIPSecPSK] = []byte(base64.StdEncoding.EncodeToString(pskSecret.Data["psk"]))
+		} else if !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "error retrieving the IPsec PSK")
+		}
+	}
+
+	bundle := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      names.ForClusterJoinBundle(request.Spec.ClusterID),
+		Namespace: request.Namespace,
+	}}
+
+	err := r.createOrUpdateForJoinRequest(ctx, request, bundle, func() {
+		bundle.Type = corev1.SecretTypeOpaque
+		bundle.Data = data
+	})
+	if err != nil {
+		return err
+	}
+
+	request.Status.JoinBundleSecret = bundle.Name
+
+	return nil
+}
+
+// createOrUpdateForJoinRequest creates or updates the given object, labelled with the joining cluster's ID and owned by
+// the request so that it is removed along with it.
+func (r *BrokerReconciler) createOrUpdateForJoinRequest(ctx context.Context, request *v1alpha1.ClusterJoinRequest,
+	obj client.Object, mutate func(),
+) error {
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		mutate()
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		labels[federate.ClusterIDLabelKey] = request.Spec.ClusterID
+		obj.SetLabels(labels)
+
+		return controllerutil.SetControllerReference(request, obj, r.Client.Scheme())
+	})
+
+	return errors.Wrapf(err, "error creating or updating %T %q", obj, obj.GetName())
+}
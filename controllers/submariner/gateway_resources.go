@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -92,6 +93,8 @@ func newGatewayPodTemplate(cr *v1alpha1.Submariner, name string, podSelectorLabe
 		healthCheckMaxPacketLossCount = cr.Spec.ConnectionHealthCheck.MaxPacketLossCount
 	}
 
+	brokerAPIServer, brokerCA := activeBrokerEndpoint(cr)
+
 	volumeMounts := []corev1.VolumeMount{
 		{Name: "ipsecd", MountPath: "/etc/ipsec.d", ReadOnly: false},
 		{Name: "ipsecnss", MountPath: "/var/lib/ipsec/nss", ReadOnly: false},
@@ -182,9 +185,9 @@ func newGatewayPodTemplate(cr *v1alpha1.Submariner, name string, podSelectorLabe
 					},
 					Env: httpproxy.AddEnvVars([]corev1.EnvVar{
 						{Name: "SUBMARINER_NAMESPACE", Value: cr.Spec.Namespace},
-						{Name: "SUBMARINER_CLUSTERCIDR", Value: cr.Status.ClusterCIDR},
-						{Name: "SUBMARINER_SERVICECIDR", Value: cr.Status.ServiceCIDR},
-						{Name: "SUBMARINER_GLOBALCIDR", Value: cr.Spec.GlobalCIDR},
+						{Name: "SUBMARINER_CLUSTERCIDR", Value: strings.Join(cr.Status.ClusterCIDRs, ",")},
+						{Name: "SUBMARINER_SERVICECIDR", Value: strings.Join(cr.Status.ServiceCIDRs, ",")},
+						{Name: "SUBMARINER_GLOBALCIDR", Value: globalCIDRs(cr)},
 						{Name: "SUBMARINER_CLUSTERID", Value: cr.Spec.ClusterID},
 						{Name: "SUBMARINER_COLORCODES", Value: cr.Spec.ColorCodes},
 						{Name: "SUBMARINER_DEBUG", Value: strconv.FormatBool(cr.Spec.Debug)},
@@ -192,10 +195,10 @@ func newGatewayPodTemplate(cr *v1alpha1.Submariner, name string, podSelectorLabe
 						{Name: "AIR_GAPPED_DEPLOYMENT", Value: strconv.FormatBool(cr.Spec.AirGappedDeployment)},
 						{Name: "SUBMARINER_BROKER", Value: cr.Spec.Broker},
 						{Name: "SUBMARINER_CABLEDRIVER", Value: cr.Spec.CableDriver},
-						{Name: broker.EnvironmentVariable("ApiServer"), Value: cr.Spec.BrokerK8sApiServer},
+						{Name: broker.EnvironmentVariable("ApiServer"), Value: brokerAPIServer},
 						{Name: broker.EnvironmentVariable("ApiServerToken"), Value: cr.Spec.BrokerK8sApiServerToken},
 						{Name: broker.EnvironmentVariable("RemoteNamespace"), Value: cr.Spec.BrokerK8sRemoteNamespace},
-						{Name: broker.EnvironmentVariable("CA"), Value: cr.Spec.BrokerK8sCA},
+						{Name: broker.EnvironmentVariable("CA"), Value: brokerCA},
 						{Name: broker.EnvironmentVariable("Insecure"), Value: strconv.FormatBool(cr.Spec.BrokerK8sInsecure)},
 						{Name: broker.EnvironmentVariable("Secret"), Value: cr.Spec.BrokerK8sSecret},
 						{Name: "CE_IPSEC_PSK", Value: cr.Spec.CeIPSecPSK},
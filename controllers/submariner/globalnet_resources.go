@@ -20,6 +20,7 @@ package submariner
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/submariner-io/admiral/pkg/names"
@@ -102,6 +103,9 @@ func newGlobalnetDaemonSet(cr *v1alpha1.Submariner, name string) *appsv1.DaemonS
 								{Name: "SUBMARINER_NAMESPACE", Value: cr.Spec.Namespace},
 								{Name: "SUBMARINER_CLUSTERID", Value: cr.Spec.ClusterID},
 								{Name: "SUBMARINER_METRICSPORT", Value: globalnetMetricsServerPort},
+								{Name: "SUBMARINER_CLUSTERCIDR", Value: strings.Join(cr.Status.ClusterCIDRs, ",")},
+								{Name: "SUBMARINER_SERVICECIDR", Value: strings.Join(cr.Status.ServiceCIDRs, ",")},
+								{Name: "SUBMARINER_GLOBALCIDR", Value: globalCIDRs(cr)},
 								{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{
 									FieldRef: &corev1.ObjectFieldSelector{
 										FieldPath: "spec.nodeName",
@@ -122,3 +126,13 @@ func newGlobalnetDaemonSet(cr *v1alpha1.Submariner, name string) *appsv1.DaemonS
 		},
 	}
 }
+
+// globalCIDRs returns the comma-separated list of all the cluster's current global CIDR blocks, as expected in
+// SUBMARINER_GLOBALCIDR.
+func globalCIDRs(cr *v1alpha1.Submariner) string {
+	if cr.Status.GlobalCIDR == "" {
+		return ""
+	}
+
+	return strings.Join(append([]string{cr.Status.GlobalCIDR}, cr.Status.AdditionalGlobalCIDRs...), ",")
+}
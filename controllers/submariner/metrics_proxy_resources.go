@@ -73,7 +73,7 @@ func newMetricsProxyDaemonSet(cr *v1alpha1.Submariner) *appsv1.DaemonSet {
 		},
 	}
 
-	if cr.Spec.GlobalCIDR != "" {
+	if cr.Status.GlobalCIDR != "" {
 		daemonSet.Spec.Template.Spec.Containers = append(daemonSet.Spec.Template.Spec.Containers,
 			*metricProxyContainer(cr, "globalnet-metrics-proxy", fmt.Sprint(globalnetMetricsServicePort), globalnetMetricsServerPort))
 	}
@@ -0,0 +1,254 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	submopv1a1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	networkChangedReason   = "NetworkChanged"
+	networkDriftReason     = "NetworkDrift"
+	networkUpToDateMessage = "The discovered cluster network matches the one in use"
+
+	// Discovery is relatively expensive, and may even create an invalid Service to find the service CIDRs.
+	minNetworkDiscoveryInterval = time.Minute
+)
+
+// The namespaces and names of the ConfigMaps network plugins are configured with; changes to them trigger re-discovery.
+var (
+	cniConfigNamespaces = []string{
+		metav1.NamespaceSystem, "cilium", "kube-flannel", "ovn-kubernetes", "openshift-ovn-kubernetes",
+	}
+	cniConfigMapNames = []string{
+		"antrea-config", "calico-config", "canal-config", "cilium-config", "kube-flannel-cfg", "ovn-config",
+	}
+)
+
+var openShiftNetworkGVK = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Network"}
+
+// RequestNetworkRediscovery requests the cluster network to be re-discovered on the next reconciliation.
+func (r *Reconciler) RequestNetworkRediscovery() {
+	r.networkRediscoveryRequested.Store(true)
+}
+
+// networkRediscoveryDue returns whether the cached cluster network should be refreshed, because re-discovery was
+// requested or the configured interval has elapsed.
+func (r *Reconciler) networkRediscoveryDue(instance *submopv1a1.Submariner) bool {
+	if r.networkRediscoveryRequested.Swap(false) {
+		return true
+	}
+
+	interval := networkDiscoveryInterval(instance)
+
+	return interval > 0 && time.Since(r.networkDiscoveredAt) >= interval
+}
+
+func networkDiscoveryInterval(instance *submopv1a1.Submariner) time.Duration {
+	if instance.Spec.NetworkDiscoveryIntervalSeconds == 0 {
+		return 0
+	}
+
+	interval := time.Duration(instance.Spec.NetworkDiscoveryIntervalSeconds) * time.Second //nolint:gosec // Overflow isn't a concern
+
+	return max(interval, minNetworkDiscoveryInterval)
+}
+
+// rediscoverNetwork refreshes the discovered cluster network. Changes are only applied if the Submariner resource asks
+// for it; otherwise the cached network is kept, and the drift is reported by reconcileNetworkDrift.
+func (r *Reconciler) rediscoverNetwork(ctx context.Context, instance *submopv1a1.Submariner) {
+	r.networkDiscoveredAt = time.Now()
+
+	discovered, err := network.Discover(ctx, r.config.GeneralClient, instance.Namespace)
+	if err != nil {
+		log.Error(err, "Error re-discovering the cluster network, keeping the previously discovered one")
+		return
+	}
+
+	if discovered == nil {
+		log.Info("The cluster network couldn't be re-discovered, keeping the previously discovered one")
+		return
+	}
+
+	r.rediscoveredNetwork = discovered
+
+	changes := networkChanges(instance, r.config.ClusterNetwork, discovered)
+	if len(changes) == 0 || !instance.Spec.ApplyNetworkChanges {
+		return
+	}
+
+	message := strings.Join(changes, "; ")
+
+	log.Info("Applying the re-discovered cluster network", "changes", message)
+	discovered.Log(log)
+
+	r.config.EventRecorder.Event(instance, corev1.EventTypeNormal, networkChangedReason,
+		"Applying the re-discovered cluster network: "+message)
+
+	r.config.ClusterNetwork = discovered
+}
+
+// reconcileNetworkDrift sets the NetworkDrift condition from the differences between the re-discovered cluster network
+// and the one in use, emitting an event when they change. The condition is removed if the network wasn't re-discovered.
+func (r *Reconciler) reconcileNetworkDrift(instance *submopv1a1.Submariner) {
+	if r.rediscoveredNetwork == nil {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, submopv1a1.NetworkDriftCondition)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               submopv1a1.NetworkDriftCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NetworkUpToDate",
+		Message:            networkUpToDateMessage,
+		ObservedGeneration: instance.Generation,
+	}
+
+	changes := networkChanges(instance, r.config.ClusterNetwork, r.rediscoveredNetwork)
+	if len(changes) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = networkDriftReason
+		condition.Message = "The re-discovered cluster network differs from the one in use: " + strings.Join(changes, "; ")
+
+		if condition.Message != r.reportedNetworkDrift {
+			log.Info("The re-discovered cluster network differs from the one in use", "changes", changes)
+			r.config.EventRecorder.Event(instance, corev1.EventTypeWarning, networkDriftReason,
+				condition.Message+"; set applyNetworkChanges to apply it")
+		}
+	}
+
+	r.reportedNetworkDrift = ""
+	if condition.Status == metav1.ConditionTrue {
+		r.reportedNetworkDrift = condition.Message
+	}
+
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+}
+
+// networkChanges describes the differences between the cluster network in use and the discovered one. CIDRs configured
+// in the Submariner resource take precedence over discovered ones, so changes to the latter don't matter.
+func networkChanges(instance *submopv1a1.Submariner, inUse, discovered *network.ClusterNetwork) []string {
+	var changes []string
+
+	if discovered.NetworkPlugin != inUse.NetworkPlugin {
+		changes = append(changes, fmt.Sprintf("the network plugin changed from %q to %q", inUse.NetworkPlugin,
+			discovered.NetworkPlugin))
+	}
+
+	if len(configuredCIDRs(instance.Spec.ClusterCIDRs, instance.Spec.ClusterCIDR)) == 0 &&
+		!slices.Equal(discovered.PodCIDRs, inUse.PodCIDRs) {
+		changes = append(changes, fmt.Sprintf("the cluster CIDRs changed from %v to %v", inUse.PodCIDRs, discovered.PodCIDRs))
+	}
+
+	if len(configuredCIDRs(instance.Spec.ServiceCIDRs, instance.Spec.ServiceCIDR)) == 0 &&
+		!slices.Equal(discovered.ServiceCIDRs, inUse.ServiceCIDRs) {
+		changes = append(changes, fmt.Sprintf("the service CIDRs changed from %v to %v", inUse.ServiceCIDRs,
+			discovered.ServiceCIDRs))
+	}
+
+	return changes
+}
+
+// watchNetworkConfiguration requests re-discovery when the OpenShift Network or the network plugins' ConfigMaps change.
+// They live outside the operator namespace, so they're watched through a dedicated cache. Only their metadata is
+// needed, and cached: a field selector can't pick several ConfigMaps by name, so every ConfigMap in the watched
+// namespaces is seen.
+func (r *Reconciler) watchNetworkConfiguration(mgr ctrl.Manager, controllerBuilder *builder.Builder) error {
+	namespaces := map[string]cache.Config{}
+	for _, namespace := range cniConfigNamespaces {
+		namespaces[namespace] = cache.Config{}
+	}
+
+	networkCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:            mgr.GetScheme(),
+		Mapper:            mgr.GetRESTMapper(),
+		DefaultNamespaces: namespaces,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error creating the network configuration cache")
+	}
+
+	if err := mgr.Add(networkCache); err != nil {
+		return errors.Wrap(err, "error adding the network configuration cache")
+	}
+
+	configMap := &metav1.PartialObjectMetadata{}
+	configMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+	controllerBuilder.WatchesRawSource(source.Kind[client.Object](networkCache, configMap,
+		handler.EnqueueRequestsFromMapFunc(r.networkConfigurationChanged),
+		predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return slices.Contains(cniConfigMapNames, object.GetName())
+		})))
+
+	// The Network resource is only served on OpenShift.
+	if _, err := mgr.GetRESTMapper().RESTMapping(openShiftNetworkGVK.GroupKind(), openShiftNetworkGVK.Version); err == nil {
+		openShiftNetwork := &metav1.PartialObjectMetadata{}
+		openShiftNetwork.SetGroupVersionKind(openShiftNetworkGVK)
+
+		controllerBuilder.WatchesRawSource(source.Kind[client.Object](networkCache, openShiftNetwork,
+			handler.EnqueueRequestsFromMapFunc(r.networkConfigurationChanged)))
+	}
+
+	return nil
+}
+
+func (r *Reconciler) networkConfigurationChanged(ctx context.Context, object client.Object) []reconcile.Request {
+	log.Info("The network configuration changed, re-discovering the cluster network", "kind",
+		object.GetObjectKind().GroupVersionKind().Kind, "namespace", object.GetNamespace(), "name", object.GetName())
+
+	r.RequestNetworkRediscovery()
+
+	submariners := &submopv1a1.SubmarinerList{}
+	if err := r.config.ScopedClient.List(ctx, submariners); err != nil {
+		log.Error(err, "Error listing Submariner resources to re-discover the cluster network")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(submariners.Items))
+	for i := range submariners.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: submariners.Items[i].Namespace,
+			Name:      submariners.Items[i].Name,
+		}})
+	}
+
+	return requests
+}
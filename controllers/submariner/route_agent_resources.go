@@ -20,7 +20,9 @@ package submariner
 
 import (
 	"context"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/submariner-io/admiral/pkg/names"
@@ -139,13 +141,13 @@ func newRouteAgentDaemonSet(cr *v1alpha1.Submariner, name string) *appsv1.Daemon
 								{Name: "host-run-openvswitch", MountPath: "/run/openvswitch"},
 								{Name: "host-run-ovn-ic", MountPath: "/run/ovn-ic"},
 							},
-							Env: httpproxy.AddEnvVars([]corev1.EnvVar{
+							Env: httpproxy.AddEnvVars(append([]corev1.EnvVar{
 								{Name: "SUBMARINER_NAMESPACE", Value: cr.Spec.Namespace},
 								{Name: "SUBMARINER_CLUSTERID", Value: cr.Spec.ClusterID},
 								{Name: "SUBMARINER_DEBUG", Value: strconv.FormatBool(cr.Spec.Debug)},
-								{Name: "SUBMARINER_CLUSTERCIDR", Value: cr.Status.ClusterCIDR},
-								{Name: "SUBMARINER_SERVICECIDR", Value: cr.Status.ServiceCIDR},
-								{Name: "SUBMARINER_GLOBALCIDR", Value: cr.Spec.GlobalCIDR},
+								{Name: "SUBMARINER_CLUSTERCIDR", Value: strings.Join(cr.Status.ClusterCIDRs, ",")},
+								{Name: "SUBMARINER_SERVICECIDR", Value: strings.Join(cr.Status.ServiceCIDRs, ",")},
+								{Name: "SUBMARINER_GLOBALCIDR", Value: globalCIDRs(cr)},
 								{Name: "SUBMARINER_NETWORKPLUGIN", Value: cr.Status.NetworkPlugin},
 								{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{
 									FieldRef: &corev1.ObjectFieldSelector{
@@ -155,7 +157,7 @@ func newRouteAgentDaemonSet(cr *v1alpha1.Submariner, name string) *appsv1.Daemon
 								{Name: "SUBMARINER_HEALTHCHECKENABLED", Value: strconv.FormatBool(healthCheckEnabled)},
 								{Name: "SUBMARINER_HEALTHCHECKINTERVAL", Value: strconv.FormatUint(healthCheckInterval, 10)},
 								{Name: "SUBMARINER_HEALTHCHECKMAXPACKETLOSSCOUNT", Value: strconv.FormatUint(healthCheckMaxPacketLossCount, 10)},
-							}),
+							}, networkPluginSettingsEnvVars(cr)...)),
 						},
 					},
 					ServiceAccountName: names.RouteAgentComponent,
@@ -170,3 +172,31 @@ func newRouteAgentDaemonSet(cr *v1alpha1.Submariner, name string) *appsv1.Daemon
 
 	return ds
 }
+
+// networkPluginSettingsEnvVars passes the discovered network plugin settings to the route agent, each setting in a
+// SUBMARINER_NETWORKPLUGIN_<SETTING> variable, e.g. SUBMARINER_NETWORKPLUGIN_IPIPMODE.
+func networkPluginSettingsEnvVars(cr *v1alpha1.Submariner) []corev1.EnvVar {
+	if cr.Status.NetworkDiscovery == nil {
+		return nil
+	}
+
+	settings := cr.Status.NetworkDiscovery.PluginSettings
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+
+	// Sorted so that the DaemonSet is only updated when the settings change.
+	slices.Sort(keys)
+
+	envVars := make([]corev1.EnvVar, 0, len(keys))
+	for _, key := range keys {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "SUBMARINER_NETWORKPLUGIN_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_")),
+			Value: settings[key],
+		})
+	}
+
+	return envVars
+}
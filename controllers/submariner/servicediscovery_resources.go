@@ -38,24 +38,25 @@ func (r *Reconciler) serviceDiscoveryReconciler(ctx context.Context, submariner
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		if isEnabled {
 			sd := newServiceDiscoveryCR(submariner.Namespace)
+			brokerAPIServer, brokerCA := activeBrokerEndpoint(submariner)
 
 			result, err := controllerutil.CreateOrUpdate(ctx, r.config.ScopedClient, sd, func() error {
 				sd.Spec = v1alpha1.ServiceDiscoverySpec{
 					Version:                  submariner.Spec.Version,
 					Repository:               submariner.Spec.Repository,
-					BrokerK8sCA:              submariner.Spec.BrokerK8sCA,
+					BrokerK8sCA:              brokerCA,
 					BrokerK8sRemoteNamespace: submariner.Spec.BrokerK8sRemoteNamespace,
 					BrokerK8sApiServerToken:  submariner.Spec.BrokerK8sApiServerToken,
-					BrokerK8sApiServer:       submariner.Spec.BrokerK8sApiServer,
+					BrokerK8sApiServer:       brokerAPIServer,
 					BrokerK8sInsecure:        submariner.Spec.BrokerK8sInsecure,
 					BrokerK8sSecret:          submariner.Spec.BrokerK8sSecret,
 					HaltOnCertificateError:   submariner.Spec.HaltOnCertificateError,
 					Debug:                    submariner.Spec.Debug,
 					ClusterID:                submariner.Spec.ClusterID,
 					Namespace:                submariner.Spec.Namespace,
-					GlobalnetEnabled:         submariner.Spec.GlobalCIDR != "",
+					GlobalnetEnabled:         submariner.Status.GlobalCIDR != "",
 					ClustersetIPEnabled:      submariner.Spec.ClustersetIPEnabled,
-					ClustersetIPCIDR:         submariner.Spec.ClustersetIPCIDR,
+					ClustersetIPCIDR:         submariner.Status.ClustersetIPCIDR,
 					ImageOverrides:           submariner.Spec.ImageOverrides,
 					CoreDNSCustomConfig:      submariner.Spec.CoreDNSCustomConfig,
 					NodeSelector:             submariner.Spec.NodeSelector,
@@ -0,0 +1,100 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package submariner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileBrokerCIDRConflicts reflects the conflicts between the cluster's CIDRs and those of the other member clusters,
+// found by the broker in the Cluster resources published by the gateways, in the Submariner status. This is best effort:
+// the broker is checked again on the next reconcile.
+func (r *Reconciler) reconcileBrokerCIDRConflicts(ctx context.Context, instance *v1alpha1.Submariner) {
+	if instance.Spec.BrokerK8sRemoteNamespace == "" {
+		return
+	}
+
+	brokerClient, err := r.getBrokerControllerClient(ctx, instance)
+	if err != nil {
+		log.Error(err, "Unable to access the broker to check the cluster's CIDRs")
+		return
+	}
+
+	if err := reflectBrokerCIDRConflicts(ctx, brokerClient, instance); err != nil {
+		log.Error(err, "Error retrieving the CIDR conflicts from the broker")
+	}
+}
+
+// reflectBrokerCIDRConflicts sets the Submariner CIDRsOverlap condition from the conflicts involving the cluster
+// reported by the broker. The condition is removed if the broker hasn't checked the member clusters' CIDRs.
+func reflectBrokerCIDRConflicts(ctx context.Context, brokerClient client.Client, instance *v1alpha1.Submariner) error {
+	brokers := &v1alpha1.BrokerList{}
+	if err := brokerClient.List(ctx, brokers, client.InNamespace(instance.Spec.BrokerK8sRemoteNamespace)); err != nil {
+		return errors.Wrap(err, "error listing Brokers")
+	}
+
+	for i := range brokers.Items {
+		brokerCondition := meta.FindStatusCondition(brokers.Items[i].Status.Conditions, v1alpha1.CIDRsOverlapCondition)
+		if brokerCondition == nil {
+			continue
+		}
+
+		condition := metav1.Condition{
+			Type:               v1alpha1.CIDRsOverlapCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             cidrsDisjointReason,
+			Message:            "The cluster's CIDRs don't overlap with those of the other member clusters",
+			ObservedGeneration: instance.Generation,
+		}
+
+		var messages []string
+
+		for j := range brokers.Items[i].Status.CIDRConflicts {
+			conflict := &brokers.Items[i].Status.CIDRConflicts[j]
+			if conflict.ClusterID == instance.Spec.ClusterID || conflict.OtherClusterID == instance.Spec.ClusterID {
+				messages = append(messages, cidrConflictMessage(conflict))
+			}
+		}
+
+		switch {
+		case len(messages) > 0:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = cidrsOverlapReason
+			condition.Message = strings.Join(messages, "; ")
+		case brokerCondition.Status == metav1.ConditionUnknown:
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = brokerCondition.Reason
+			condition.Message = brokerCondition.Message
+		}
+
+		meta.SetStatusCondition(&instance.Status.Conditions, condition)
+
+		return nil
+	}
+
+	meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.CIDRsOverlapCondition)
+
+	return nil
+}
@@ -22,36 +22,40 @@ import (
 	"context"
 	"encoding/base64"
 	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	"github.com/submariner-io/admiral/pkg/federate"
 	"github.com/submariner-io/admiral/pkg/finalizer"
-	level "github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/resource"
-	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/util"
 	submopv1a1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/crd"
 	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	"github.com/submariner-io/submariner-operator/pkg/gateway"
 	"github.com/submariner-io/submariner-operator/pkg/images"
 	"github.com/submariner-io/submariner-operator/pkg/names"
 	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -75,6 +79,10 @@ type Config struct {
 	ClusterNetwork               *network.ClusterNetwork
 	GetAuthorizedBrokerClientFor func(spec *submopv1a1.SubmarinerSpec, brokerToken, brokerCA string,
 		secretGVR schema.GroupVersionResource) (dynamic.Interface, error)
+	GetAuthorizedBrokerControllerClientFor func(spec *submopv1a1.SubmarinerSpec, brokerToken, brokerCA string,
+		secretGVR schema.GroupVersionResource) (client.Client, error)
+	// Required; cluster network changes and drift are reported through it.
+	EventRecorder record.EventRecorder
 }
 
 // Reconciler reconciles a Submariner object.
@@ -94,12 +102,37 @@ type Reconciler struct {
 	//   using the information from the new secret;
 	// - watch for changes to the secret, and if it changes, update the target secret.
 	// Tokens map back to their SA, so we can do both the above by watching tokens only.
-	// Since the synchronisation ends up being specific to a Submariner CR secret, we track one syncer per Submariner CR secret name.
-	// We don't keep track of the secret syncers themselves, just their cancel functions.
-	secretSyncCancelFuncs map[string]context.CancelFunc
-	syncerMutex           sync.Mutex
+	// Since the synchronisation ends up being specific to the broker a Submariner CR points to, we track one syncer per
+	// broker identity (API server, remote namespace, cluster ID and secret name). If any of these change, the old syncer
+	// is cancelled and a new one started against the new broker.
+	secretSyncers map[brokerSecretSyncerKey]*brokerSecretSyncer
+	syncerMutex   sync.Mutex
+
+	// The cluster's heartbeat is renewed on the broker it is connected to, as identified by the key; a new heartbeat is
+	// started if the broker changes.
+	brokerHeartbeats map[brokerHeartbeatKey]context.CancelFunc
+
+	// The controller-runtime clients used to access the brokers, keyed by Submariner namespace. Building a client checks
+	// the broker credentials, so each is reused until the broker or its credentials change.
+	brokerControllerClients map[string]cachedBrokerControllerClient
 
 	networkPluginSyncerRemoved bool
+
+	// The dataplane CRDs are installed, and Gateways watched, once a Submariner is deployed.
+	dataplaneCRDsEnsured bool
+	watchGateways        func() error
+
+	// The brokers on which globalnet was found to be disabled, keyed by API server and namespace, with the time at which
+	// it was.
+	globalnetDisabledBrokers map[string]time.Time
+
+	// The cached cluster network, in r.config.ClusterNetwork, is refreshed periodically and when the network
+	// configuration changes; rediscoveredNetwork is the latest discovery, which differs from the cached one if changes
+	// aren't applied.
+	networkDiscoveredAt         time.Time
+	networkRediscoveryRequested atomic.Bool
+	rediscoveredNetwork         *network.ClusterNetwork
+	reportedNetworkDrift        string
 }
 
 // blank assignment to verify that Reconciler implements reconcile.Reconciler.
@@ -108,15 +141,26 @@ var _ reconcile.Reconciler = &Reconciler{}
 // NewReconciler returns a new Reconciler.
 func NewReconciler(config *Config) *Reconciler {
 	r := &Reconciler{
-		config:                *config,
-		log:                   ctrl.Log.WithName("controllers").WithName("Submariner"),
-		secretSyncCancelFuncs: make(map[string]context.CancelFunc),
+		config:                   *config,
+		log:                      ctrl.Log.WithName("controllers").WithName("Submariner"),
+		secretSyncers:            make(map[brokerSecretSyncerKey]*brokerSecretSyncer),
+		brokerHeartbeats:         map[brokerHeartbeatKey]context.CancelFunc{},
+		brokerControllerClients:  map[string]cachedBrokerControllerClient{},
+		globalnetDisabledBrokers: map[string]time.Time{},
 	}
 
 	if r.config.GetAuthorizedBrokerClientFor == nil {
 		r.config.GetAuthorizedBrokerClientFor = getAuthorizedBrokerClientFor
 	}
 
+	if r.config.GetAuthorizedBrokerControllerClientFor == nil {
+		r.config.GetAuthorizedBrokerControllerClientFor = func(spec *submopv1a1.SubmarinerSpec, brokerToken, brokerCA string,
+			secretGVR schema.GroupVersionResource,
+		) (client.Client, error) {
+			return getAuthorizedBrokerControllerClientFor(spec, brokerToken, brokerCA, secretGVR, r.config.Scheme)
+		}
+	}
+
 	return r
 }
 
@@ -155,16 +199,27 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	if !instance.GetDeletionTimestamp().IsZero() {
 		log.Info("Submariner is being deleted")
 		r.cancelSecretSyncer(instance)
+		r.cancelBrokerHeartbeatsFor(instance.Namespace, log)
+		r.forgetGlobalnetDisabledBrokers(instance)
 
 		return r.runComponentCleanup(ctx, instance)
 	}
 
+	if err := r.ensureDataplaneCRDs(ctx); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	initialStatus := instance.Status.DeepCopy()
+
+	r.selectActiveBrokerEndpoint(ctx, instance, reqLogger)
+
 	// Ensure we have a secret syncer
 	if err := r.setupSecretSyncer(ctx, instance, reqLogger, request.Namespace); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	initialStatus := instance.Status.DeepCopy()
+	// Ensure the cluster's heartbeat is renewed on the broker
+	r.setupBrokerHeartbeat(ctx, instance, reqLogger)
 
 	// This has the side effect of setting the CIDRs in the Submariner instance.
 	_, err = r.discoverNetwork(ctx, instance, reqLogger)
@@ -172,6 +227,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 
+	// Allocation failures are reported in the status, and returned once the rest of the reconcile has completed.
+	allocationPending, allocationErr := r.allocateBrokerCIDRs(ctx, instance)
+
+	r.reconcileBrokerCIDRConflicts(ctx, instance)
+
 	gatewayDaemonSet, err := r.reconcileGatewayDaemonSet(ctx, instance, reqLogger)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -192,7 +252,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 
 	var globalnetDaemonSet *appsv1.DaemonSet
 
-	if instance.Spec.GlobalCIDR != "" {
+	if instance.Status.GlobalCIDR != "" {
 		if globalnetDaemonSet, err = r.reconcileGlobalnetDaemonSet(ctx, instance, reqLogger); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -224,9 +284,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	instance.Status.AirGappedDeployment = instance.Spec.AirGappedDeployment
 	instance.Status.ColorCodes = instance.Spec.ColorCodes
 	instance.Status.ClusterID = instance.Spec.ClusterID
-	instance.Status.GlobalCIDR = instance.Spec.GlobalCIDR
-	instance.Status.ClustersetIPCIDR = instance.Spec.ClustersetIPCIDR
+	instance.Status.GlobalnetClusterSize = 0
+
+	// The size granted by the broker is only reported once the allocation has succeeded.
+	if !meta.IsStatusConditionTrue(instance.Status.Conditions, submopv1a1.BrokerCIDRAllocationFailedCondition) {
+		instance.Status.GlobalnetClusterSize = globalCIDRSize(instance.Status.GlobalCIDR)
+	}
+
 	instance.Status.Gateways = &gatewayStatuses
+	instance.Status.BrokerSecretSyncer = r.secretSyncerStatus(instance)
 
 	err = updateDaemonSetStatus(ctx, r.config.ScopedClient, gatewayDaemonSet, &instance.Status.GatewayDaemonSetStatus, request.Namespace)
 	if err != nil {
@@ -268,7 +334,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		}
 	}
 
-	return reconcile.Result{}, nil
+	if allocationErr != nil {
+		return reconcile.Result{}, allocationErr
+	}
+
+	if allocationPending {
+		return reconcile.Result{RequeueAfter: brokerCIDRAllocationRetryInterval}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: requeueInterval(instance)}, nil
+}
+
+// requeueInterval returns the shortest of the intervals at which the broker health and the cluster network are checked,
+// or zero if neither is checked periodically.
+func requeueInterval(instance *submopv1a1.Submariner) time.Duration {
+	intervals := []time.Duration{brokerHealthCheckInterval(instance), networkDiscoveryInterval(instance)}
+	intervals = slices.DeleteFunc(intervals, func(interval time.Duration) bool {
+		return interval == 0
+	})
+
+	if len(intervals) == 0 {
+		return 0
+	}
+
+	return slices.Min(intervals)
 }
 
 func getImagePath(submariner *submopv1a1.Submariner, imageName, componentName string) string {
@@ -305,106 +394,136 @@ func (r *Reconciler) addFinalizer(ctx context.Context, instance *submopv1a1.Subm
 }
 
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// Watch for changes to the gateway status in the same namespace
-	mapFn := handler.MapFunc(
-		func(_ context.Context, object client.Object) []reconcile.Request {
-			return []reconcile.Request{
-				{NamespacedName: types.NamespacedName{
-					Name:      "submariner",
-					Namespace: object.GetNamespace(),
-				}},
-			}
-		})
-
-	//nolint:wrapcheck // No need to wrap here
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		Named("submariner-controller").
 		// Watch for changes to primary resource Submariner
 		For(&submopv1a1.Submariner{}).
 		// Watch for changes to secondary resource DaemonSets and requeue the owner Submariner
-		Owns(&appsv1.DaemonSet{}).
-		Watches(&submv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(mapFn)).
-		Complete(r)
-}
+		Owns(&appsv1.DaemonSet{})
 
-func (r *Reconciler) setupSecretSyncer(ctx context.Context, instance *submopv1a1.Submariner, logger logr.Logger, namespace string) error {
-	r.syncerMutex.Lock()
-	defer r.syncerMutex.Unlock()
-
-	if instance.Spec.BrokerK8sSecret != "" {
-		if _, ok := r.secretSyncCancelFuncs[instance.Spec.BrokerK8sSecret]; !ok {
-			brokerClient, err := r.getBrokerClient(ctx, instance)
-			if err != nil {
-				return err
-			}
-
-			clusterID := instance.Spec.ClusterID
-			transformedSecretName := instance.Spec.BrokerK8sSecret
-
-			secretSyncer, err := syncer.NewResourceSyncer(
-				&syncer.ResourceSyncerConfig{
-					Name:            "Broker secret syncer",
-					ResourceType:    &corev1.Secret{},
-					SourceClient:    brokerClient,
-					SourceNamespace: instance.Spec.BrokerK8sRemoteNamespace,
-					Direction:       syncer.None,
-					RestMapper:      r.config.ScopedClient.RESTMapper(),
-					Scheme:          r.config.Scheme,
-					Federator: federate.NewCreateOrUpdateFederator(
-						r.config.DynClient, r.config.ScopedClient.RESTMapper(), namespace, ""),
-					Transform: func(from runtime.Object, _ int, _ syncer.Operation) (runtime.Object, bool) {
-						secret := from.(*corev1.Secret)
-						logger.V(level.TRACE).Info("Transforming secret", "secret", secret)
-						if saName, ok := secret.ObjectMeta.Annotations[corev1.ServiceAccountNameKey]; ok &&
-							saName == names.ForClusterSA(clusterID) {
-							transformedSecret := &corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									Name: transformedSecretName,
-								},
-								Type: corev1.SecretTypeOpaque,
-								Data: secret.Data,
-							}
-							logger.V(level.TRACE).Info("Transformed secret", "transformedSecret", transformedSecret)
-							return transformedSecret, false
-						}
-						return nil, false
-					},
-				})
-			if err != nil {
-				return errors.Wrap(err, "error building a resource syncer for secrets")
-			}
-
-			ctx, cancelFunc := context.WithCancel(context.TODO())
-			if err := secretSyncer.Start(ctx.Done()); err != nil {
-				cancelFunc()
-				return errors.Wrap(err, "error starting the secret syncer")
-			}
-
-			r.secretSyncCancelFuncs[instance.Spec.BrokerK8sSecret] = cancelFunc
-		}
+	if err := r.watchNetworkConfiguration(mgr, controllerBuilder); err != nil {
+		return err
+	}
+
+	submarinerController, err := controllerBuilder.Build(r)
+	if err != nil {
+		return errors.Wrap(err, "error building the Submariner controller")
+	}
+
+	// Watch for changes to the gateway status in the same namespace, once the Gateway CRD is installed
+	r.watchGateways = func() error {
+		mapFn := handler.MapFunc(
+			func(_ context.Context, object client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{
+						Name:      "submariner",
+						Namespace: object.GetNamespace(),
+					}},
+				}
+			})
+
+		//nolint:wrapcheck // No need to wrap here
+		return submarinerController.Watch(source.Kind[client.Object](mgr.GetCache(), &submv1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(mapFn)))
 	}
 
 	return nil
 }
 
-func (r *Reconciler) cancelSecretSyncer(instance *submopv1a1.Submariner) {
-	r.syncerMutex.Lock()
-	defer r.syncerMutex.Unlock()
+// ensureDataplaneCRDs installs the dataplane CRDs, which are only needed on clusters running Submariner, and starts
+// watching Gateways.
+func (r *Reconciler) ensureDataplaneCRDs(ctx context.Context) error {
+	if r.dataplaneCRDsEnsured {
+		return nil
+	}
+
+	if err := gateway.Ensure(ctx, crd.UpdaterFromControllerClient(r.config.GeneralClient)); err != nil {
+		return err //nolint:wrapcheck // Errors are already wrapped
+	}
 
-	if instance.Spec.BrokerK8sSecret != "" {
-		if cancelFunc, ok := r.secretSyncCancelFuncs[instance.Spec.BrokerK8sSecret]; ok {
-			cancelFunc()
-			delete(r.secretSyncCancelFuncs, instance.Spec.BrokerK8sSecret)
+	if r.watchGateways != nil {
+		if err := r.watchGateways(); err != nil {
+			return errors.Wrap(err, "error watching Gateways")
 		}
 	}
+
+	r.dataplaneCRDsEnsured = true
+
+	return nil
 }
 
 func (r *Reconciler) getBrokerClient(ctx context.Context, instance *submopv1a1.Submariner) (dynamic.Interface, error) {
-	spec := &instance.Spec
+	apiServer, _ := activeBrokerEndpoint(instance)
+
+	return r.getBrokerClientFor(ctx, instance, apiServer)
+}
+
+// getBrokerClientFor returns a client authorized to access the broker through the given API server, which must be either
+// BrokerK8sApiServer or one of the BrokerK8sEndpoints.
+func (r *Reconciler) getBrokerClientFor(ctx context.Context, instance *submopv1a1.Submariner, apiServer string,
+) (dynamic.Interface, error) {
+	spec, brokerToken, brokerCA, secretGVR, err := r.getBrokerCredentialsFor(ctx, instance, apiServer)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.config.GetAuthorizedBrokerClientFor(spec, brokerToken, brokerCA, *secretGVR)
+}
+
+// getBrokerControllerClient returns a controller-runtime client authorized to access the broker through the active
+// endpoint. The client is reused across reconciles while the broker and its credentials are unchanged; it doesn't read
+// from an informer cache.
+func (r *Reconciler) getBrokerControllerClient(ctx context.Context, instance *submopv1a1.Submariner) (client.Client, error) {
+	apiServer, _ := activeBrokerEndpoint(instance)
+
+	spec, brokerToken, brokerCA, secretGVR, err := r.getBrokerCredentialsFor(ctx, instance, apiServer)
+	if err != nil {
+		return nil, err
+	}
+
+	key := brokerControllerClientKey{
+		apiServer:       spec.BrokerK8sApiServer,
+		remoteNamespace: spec.BrokerK8sRemoteNamespace,
+		insecure:        spec.BrokerK8sInsecure,
+		token:           brokerToken,
+		ca:              brokerCA,
+	}
+
+	if cached, ok := r.brokerControllerClients[instance.Namespace]; ok && cached.key == key {
+		return cached.client, nil
+	}
+
+	brokerClient, err := r.config.GetAuthorizedBrokerControllerClientFor(spec, brokerToken, brokerCA, *secretGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	r.brokerControllerClients[instance.Namespace] = cachedBrokerControllerClient{key: key, client: brokerClient}
+
+	return brokerClient, nil
+}
+
+type brokerControllerClientKey struct {
+	apiServer       string
+	remoteNamespace string
+	insecure        bool
+	token           string
+	ca              string
+}
+
+type cachedBrokerControllerClient struct {
+	key    brokerControllerClientKey
+	client client.Client
+}
+
+// getBrokerCredentialsFor returns the spec, token and CA to use to access the broker through the given API server.
+func (r *Reconciler) getBrokerCredentialsFor(ctx context.Context, instance *submopv1a1.Submariner, apiServer string,
+) (*submopv1a1.SubmarinerSpec, string, string, *schema.GroupVersionResource, error) {
+	spec := instance.Spec.DeepCopy()
 
 	_, secretGVR, err := util.ToUnstructuredResource(&corev1.Secret{}, r.config.ScopedClient.RESTMapper())
 	if err != nil {
-		return nil, errors.Wrap(err, "error calculating the GVR for the Secret type")
+		return nil, "", "", nil, errors.Wrap(err, "error calculating the GVR for the Secret type")
 	}
 
 	// We can't use files here since we don't have a mounted secret so read the broker Secret CR.
@@ -418,14 +537,49 @@ func (r *Reconciler) getBrokerClient(ctx context.Context, instance *submopv1a1.S
 		brokerToken = string(brokerSecret.Data["token"])
 		brokerCA = base64.StdEncoding.EncodeToString(brokerSecret.Data["ca.crt"])
 	} else if !apierrors.IsNotFound(err) {
-		return nil, errors.Wrapf(err, "error retrieving broker secret %q", spec.BrokerK8sSecret)
+		return nil, "", "", nil, errors.Wrapf(err, "error retrieving broker secret %q", spec.BrokerK8sSecret)
 	}
 
-	return r.config.GetAuthorizedBrokerClientFor(spec, brokerToken, brokerCA, *secretGVR)
+	// Each broker endpoint may be served with its own certificate authority, which takes precedence.
+	if endpoint := findBrokerEndpoint(spec, apiServer); endpoint != nil {
+		spec.BrokerK8sApiServer = endpoint.ApiServer
+
+		if endpoint.CA != "" {
+			brokerCA = endpoint.CA
+		}
+	}
+
+	return spec, brokerToken, brokerCA, secretGVR, nil
 }
 
 func getAuthorizedBrokerClientFor(spec *submopv1a1.SubmarinerSpec, brokerToken, brokerCA string, secretGVR schema.GroupVersionResource,
 ) (dynamic.Interface, error) {
+	brokerConfig, err := getAuthorizedBrokerRestConfigFor(spec, brokerToken, brokerCA, secretGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	brokerClient, err := dynamic.NewForConfig(brokerConfig)
+
+	return brokerClient, errors.Wrap(err, "error building a dynamic client for the broker")
+}
+
+func getAuthorizedBrokerControllerClientFor(spec *submopv1a1.SubmarinerSpec, brokerToken, brokerCA string,
+	secretGVR schema.GroupVersionResource, scheme *runtime.Scheme,
+) (client.Client, error) {
+	brokerConfig, err := getAuthorizedBrokerRestConfigFor(spec, brokerToken, brokerCA, secretGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	brokerClient, err := client.New(brokerConfig, client.Options{Scheme: scheme})
+
+	return brokerClient, errors.Wrap(err, "error building a controller client for the broker")
+}
+
+func getAuthorizedBrokerRestConfigFor(spec *submopv1a1.SubmarinerSpec, brokerToken, brokerCA string,
+	secretGVR schema.GroupVersionResource,
+) (*rest.Config, error) {
 	brokerConfig, _, err := resource.GetAuthorizedRestConfigFromData(
 		spec.BrokerK8sApiServer,
 		brokerToken,
@@ -433,11 +587,6 @@ func getAuthorizedBrokerClientFor(spec *submopv1a1.SubmarinerSpec, brokerToken,
 		&rest.TLSClientConfig{Insecure: spec.BrokerK8sInsecure},
 		secretGVR,
 		spec.BrokerK8sRemoteNamespace)
-	if err != nil {
-		return nil, errors.Wrap(err, "error building an authorized RestConfig for the broker")
-	}
 
-	brokerClient, err := dynamic.NewForConfig(brokerConfig)
-
-	return brokerClient, errors.Wrap(err, "error building a dynamic client for the broker")
+	return brokerConfig, errors.Wrap(err, "error building an authorized RestConfig for the broker")
 }
@@ -29,24 +29,36 @@ import (
 	. "github.com/onsi/gomega"
 	v1config "github.com/openshift/api/config/v1"
 	"github.com/submariner-io/admiral/pkg/fake"
+	"github.com/submariner-io/admiral/pkg/federate"
 	"github.com/submariner-io/admiral/pkg/names"
+	"github.com/submariner-io/admiral/pkg/reporter"
 	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/syncer/broker"
 	syncertest "github.com/submariner-io/admiral/pkg/syncer/test"
 	testutil "github.com/submariner-io/admiral/pkg/test"
 	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	submarinerController "github.com/submariner-io/submariner-operator/controllers/submariner"
 	"github.com/submariner-io/submariner-operator/controllers/test"
 	"github.com/submariner-io/submariner-operator/controllers/uninstall"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
 	opnames "github.com/submariner-io/submariner-operator/pkg/names"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cni"
 	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -74,6 +86,13 @@ func testReconciliation() {
 		t.awaitFinalizer()
 	})
 
+	It("should install the dataplane CRDs", func(ctx SpecContext) {
+		t.AssertReconcileSuccess(ctx)
+
+		crd := &apiextensions.CustomResourceDefinition{}
+		Expect(t.GeneralClient.Get(ctx, client.ObjectKey{Name: "gateways.submariner.io"}, crd)).To(Succeed())
+	})
+
 	Context("", func() {
 		BeforeEach(func() {
 			t.submariner.Spec.NatEnabled = true
@@ -88,6 +107,7 @@ func testReconciliation() {
 			Expect(updated.Status.AirGappedDeployment).To(BeTrue())
 			Expect(updated.Status.ClusterID).To(Equal(t.submariner.Spec.ClusterID))
 			Expect(updated.Status.GlobalCIDR).To(Equal(t.submariner.Spec.GlobalCIDR))
+			Expect(updated.Status.GlobalnetClusterSize).To(Equal(uint(65536)))
 			Expect(updated.Status.NetworkPlugin).To(Equal(t.clusterNetwork.NetworkPlugin))
 			Expect(updated.Status.Version).To(Equal(t.submariner.Spec.Version))
 		})
@@ -100,6 +120,12 @@ func testReconciliation() {
 			updated := t.getSubmariner(ctx)
 			Expect(updated.Status.ServiceCIDR).To(Equal(testDetectedServiceCIDR))
 			Expect(updated.Status.ClusterCIDR).To(Equal(testDetectedClusterCIDR))
+			Expect(updated.Status.NetworkDiscovery).To(Equal(&v1alpha1.NetworkDiscoveryStatus{
+				ClusterCIDRsSource:     string(network.SourceNodeSpec),
+				ClusterCIDRsConfidence: string(network.ConfidenceMedium),
+				ServiceCIDRsSource:     string(network.SourceServiceCIDR),
+				NetworkPluginEvidence:  "Pod kube-system/fake",
+			}))
 		})
 	})
 
@@ -118,6 +144,504 @@ func testReconciliation() {
 			updated := t.getSubmariner(ctx)
 			Expect(updated.Status.ServiceCIDR).To(Equal(testConfiguredServiceCIDR))
 			Expect(updated.Status.ClusterCIDR).To(Equal(testConfiguredClusterCIDR))
+			Expect(updated.Status.NetworkDiscovery.ClusterCIDRsSource).To(Equal("Spec"))
+			Expect(updated.Status.NetworkDiscovery.ClusterCIDRsConfidence).To(BeEmpty())
+			Expect(updated.Status.NetworkDiscovery.ServiceCIDRsSource).To(Equal("Spec"))
+		})
+	})
+
+	When("multiple network CIDRs are detected", func() {
+		BeforeEach(func() {
+			t.clusterNetwork.PodCIDRs = []string{testDetectedClusterCIDR, "fd00:10:244::/56"}
+			t.clusterNetwork.ServiceCIDRs = []string{testDetectedServiceCIDR, "fd00:10:96::/112"}
+		})
+
+		It("should use all of them", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			updated := t.getSubmariner(ctx)
+			Expect(updated.Status.ClusterCIDRs).To(Equal(t.clusterNetwork.PodCIDRs))
+			Expect(updated.Status.ClusterCIDR).To(Equal(testDetectedClusterCIDR))
+			Expect(updated.Status.ServiceCIDRs).To(Equal(t.clusterNetwork.ServiceCIDRs))
+			Expect(updated.Status.ServiceCIDR).To(Equal(testDetectedServiceCIDR))
+
+			t.assertGatewayDaemonSet(ctx)
+			t.assertRouteAgentDaemonSet(ctx)
+		})
+	})
+
+	When("multiple network CIDRs are provided", func() {
+		BeforeEach(func() {
+			t.submariner.Spec.ClusterCIDR = testConfiguredClusterCIDR
+			t.submariner.Spec.ClusterCIDRs = []string{"fd00:10:67::/56", testConfiguredClusterCIDR}
+			t.submariner.Spec.ServiceCIDRs = []string{"fd00:10:66::/112", testConfiguredServiceCIDR}
+		})
+
+		It("should use them instead of the singular and detected ones", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			updated := t.getSubmariner(ctx)
+			Expect(updated.Status.ClusterCIDRs).To(Equal(t.submariner.Spec.ClusterCIDRs))
+			Expect(updated.Status.ClusterCIDR).To(Equal("fd00:10:67::/56"))
+			Expect(updated.Status.ServiceCIDRs).To(Equal(t.submariner.Spec.ServiceCIDRs))
+			Expect(updated.Status.ServiceCIDR).To(Equal("fd00:10:66::/112"))
+
+			t.assertGatewayDaemonSet(ctx)
+			t.assertRouteAgentDaemonSet(ctx)
+		})
+	})
+
+	When("the cluster network is re-discovered", func() {
+		const rediscoveredClusterCIDR = "10.245.0.0/16"
+
+		BeforeEach(func() {
+			t.InitGeneralClientObjs = append(t.InitGeneralClientObjs,
+				newControlPlanePod("kube-controller-manager", "--cluster-cidr="+rediscoveredClusterCIDR),
+				newControlPlanePod("kube-apiserver", "--service-cluster-ip-range="+testDetectedServiceCIDR))
+		})
+
+		Context("periodically", func() {
+			BeforeEach(func() {
+				t.submariner.Spec.NetworkDiscoveryIntervalSeconds = 300
+			})
+
+			assertReconcileRequeuedForRediscovery := func(ctx context.Context) {
+				Expect(t.DoReconcile(ctx)).To(Equal(reconcile.Result{RequeueAfter: 300 * time.Second}))
+			}
+
+			It("should report the drift without applying it", func(ctx SpecContext) {
+				assertReconcileRequeuedForRediscovery(ctx)
+
+				updated := t.getSubmariner(ctx)
+				Expect(updated.Status.ClusterCIDR).To(Equal(testDetectedClusterCIDR))
+				Expect(updated.Status.NetworkPlugin).To(Equal(t.clusterNetwork.NetworkPlugin))
+
+				condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.NetworkDriftCondition)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Message).To(ContainSubstring(rediscoveredClusterCIDR))
+				Expect(condition.Message).To(ContainSubstring(cni.Generic))
+
+				Expect(t.eventRecorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " NetworkDrift")))
+
+				assertReconcileRequeuedForRediscovery(ctx)
+				Expect(t.eventRecorder.Events).NotTo(Receive())
+
+				routeAgent := t.AssertDaemonSet(ctx, names.RouteAgentComponent)
+				Expect(test.EnvMapFrom(routeAgent)).To(HaveKeyWithValue("SUBMARINER_NETWORKPLUGIN", t.clusterNetwork.NetworkPlugin))
+			})
+
+			Context("and network changes are applied", func() {
+				BeforeEach(func() {
+					t.submariner.Spec.ApplyNetworkChanges = true
+				})
+
+				It("should roll the components with the re-discovered network", func(ctx SpecContext) {
+					assertReconcileRequeuedForRediscovery(ctx)
+
+					updated := t.getSubmariner(ctx)
+					Expect(updated.Status.ClusterCIDR).To(Equal(rediscoveredClusterCIDR))
+					Expect(updated.Status.NetworkPlugin).To(Equal(cni.Generic))
+					Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, v1alpha1.NetworkDriftCondition)).To(BeTrue())
+
+					Expect(t.eventRecorder.Events).To(Receive(HavePrefix(corev1.EventTypeNormal + " NetworkChanged")))
+
+					routeAgent := t.AssertDaemonSet(ctx, names.RouteAgentComponent)
+					Expect(test.EnvMapFrom(routeAgent)).To(HaveKeyWithValue("SUBMARINER_NETWORKPLUGIN", cni.Generic))
+					Expect(test.EnvMapFrom(routeAgent)).To(HaveKeyWithValue("SUBMARINER_CLUSTERCIDR", rediscoveredClusterCIDR))
+				})
+			})
+
+			Context("and the network hasn't changed", func() {
+				BeforeEach(func() {
+					t.clusterNetwork.NetworkPlugin = cni.Generic
+					t.clusterNetwork.PodCIDRs = []string{rediscoveredClusterCIDR}
+				})
+
+				It("should report that the network is up to date", func(ctx SpecContext) {
+					assertReconcileRequeuedForRediscovery(ctx)
+
+					updated := t.getSubmariner(ctx)
+					Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, v1alpha1.NetworkDriftCondition)).To(BeTrue())
+					Expect(t.eventRecorder.Events).NotTo(Receive())
+				})
+			})
+		})
+
+		Context("with an interval shorter than a minute", func() {
+			BeforeEach(func() {
+				t.submariner.Spec.NetworkDiscoveryIntervalSeconds = 1
+			})
+
+			It("should re-discover it every minute", func(ctx SpecContext) {
+				Expect(t.DoReconcile(ctx)).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+			})
+		})
+
+		Context("on request", func() {
+			It("should report the drift", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+				Expect(meta.FindStatusCondition(t.getSubmariner(ctx).Status.Conditions, v1alpha1.NetworkDriftCondition)).To(BeNil())
+
+				t.Controller.(*submarinerController.Reconciler).RequestNetworkRediscovery()
+				t.AssertReconcileSuccess(ctx)
+				Expect(meta.IsStatusConditionTrue(t.getSubmariner(ctx).Status.Conditions, v1alpha1.NetworkDriftCondition)).To(BeTrue())
+			})
+		})
+	})
+
+	When("the cluster is connected to a broker", func() {
+		var brokerResource *v1alpha1.Broker
+
+		BeforeEach(func() {
+			brokerResource = &v1alpha1.Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "submariner-broker",
+					Namespace: t.submariner.Spec.BrokerK8sRemoteNamespace,
+				},
+			}
+		})
+
+		JustBeforeEach(func(ctx SpecContext) {
+			Expect(t.brokerClient.Create(ctx, brokerResource)).To(Succeed())
+		})
+
+		getCIDRsOverlapCondition := func(ctx context.Context) *metav1.Condition {
+			return meta.FindStatusCondition(t.getSubmariner(ctx).Status.Conditions, v1alpha1.CIDRsOverlapCondition)
+		}
+
+		It("should leave the cluster's Cluster resource on the broker to the gateway", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			err := t.brokerClient.Get(ctx, client.ObjectKey{
+				Namespace: t.submariner.Spec.BrokerK8sRemoteNamespace,
+				Name:      t.submariner.Spec.ClusterID,
+			}, &submarinerv1.Cluster{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			Expect(getCIDRsOverlapCondition(ctx)).To(BeNil())
+		})
+
+		It("should reuse the broker client across reconciles", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+			t.AssertReconcileSuccess(ctx)
+
+			Expect(t.brokerControllerClientsBuilt).To(Equal(1))
+		})
+
+		Context("and the broker has created the cluster's heartbeat Lease", func() {
+			leases := func() dynamic.ResourceInterface {
+				return t.dynClient.Resource(coordinationv1.SchemeGroupVersion.WithResource("leases")).Namespace(
+					t.submariner.Spec.BrokerK8sRemoteNamespace)
+			}
+
+			BeforeEach(func() {
+				Expect(t.submariner.Spec.BrokerK8sSecret).To(BeEmpty())
+
+				syncertest.CreateResource(leases(), &coordinationv1.Lease{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        opnames.ForClusterHeartbeat(t.submariner.Spec.ClusterID),
+						Namespace:   t.submariner.Spec.BrokerK8sRemoteNamespace,
+						Labels:      map[string]string{federate.ClusterIDLabelKey: t.submariner.Spec.ClusterID},
+						Annotations: map[string]string{"submariner.io/departed": "2024-01-01T00:00:00Z"},
+					},
+				})
+			})
+
+			It("should renew it, preserving its annotations", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				testutil.AwaitAndVerifyResource(resource.ForDynamic(leases()), opnames.ForClusterHeartbeat(t.submariner.Spec.ClusterID),
+					func(obj *unstructured.Unstructured) bool {
+						lease := resource.MustFromUnstructured(obj, &coordinationv1.Lease{})
+						return lease.Spec.RenewTime != nil && lease.Spec.HolderIdentity != nil &&
+							*lease.Spec.HolderIdentity == t.submariner.Spec.ClusterID
+					})
+
+				obj, err := leases().Get(ctx, opnames.ForClusterHeartbeat(t.submariner.Spec.ClusterID), metav1.GetOptions{})
+				Expect(err).To(Succeed())
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue("submariner.io/departed", "2024-01-01T00:00:00Z"))
+				Expect(obj.GetLabels()).To(HaveKeyWithValue(federate.ClusterIDLabelKey, t.submariner.Spec.ClusterID))
+			})
+		})
+
+		Context("and the broker reports that the cluster's CIDRs overlap with another cluster's", func() {
+			BeforeEach(func() {
+				brokerResource.Status.CIDRConflicts = []v1alpha1.CIDRConflict{
+					{ClusterID: "north", CIDR: "10.0.0.0/16", OtherClusterID: "south", OtherCIDR: "10.0.0.0/16"},
+					{ClusterID: t.submariner.Spec.ClusterID, CIDR: testDetectedClusterCIDR, OtherClusterID: "west", OtherCIDR: "10.244.0.0/24"},
+				}
+				brokerResource.Status.Conditions = []metav1.Condition{{
+					Type:   v1alpha1.CIDRsOverlapCondition,
+					Status: metav1.ConditionTrue,
+					Reason: "OverlappingCIDRs",
+				}}
+			})
+
+			It("should set the CIDRsOverlap condition", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				condition := getCIDRsOverlapCondition(ctx)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Message).To(ContainSubstring("west"))
+				Expect(condition.Message).ToNot(ContainSubstring("north"))
+			})
+		})
+
+		Context("and the broker reports that only other clusters' CIDRs overlap", func() {
+			BeforeEach(func() {
+				brokerResource.Status.CIDRConflicts = []v1alpha1.CIDRConflict{
+					{ClusterID: "north", CIDR: "10.0.0.0/16", OtherClusterID: "south", OtherCIDR: "10.0.0.0/16"},
+				}
+				brokerResource.Status.Conditions = []metav1.Condition{{
+					Type:   v1alpha1.CIDRsOverlapCondition,
+					Status: metav1.ConditionTrue,
+					Reason: "OverlappingCIDRs",
+				}}
+			})
+
+			It("should clear the CIDRsOverlap condition", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				condition := getCIDRsOverlapCondition(ctx)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			})
+		})
+	})
+
+	When("the global CIDR isn't specified", func() {
+		var globalnetEnabled bool
+
+		BeforeEach(func() {
+			t.submariner.Spec.GlobalCIDR = ""
+			globalnetEnabled = true
+		})
+
+		JustBeforeEach(func(ctx SpecContext) {
+			Expect(globalnet.CreateConfigMap(ctx, t.brokerClient, globalnetEnabled, globalnet.DefaultGlobalnetCIDR,
+				globalnet.DefaultGlobalnetClusterSize, t.submariner.Spec.BrokerK8sRemoteNamespace)).To(Succeed())
+		})
+
+		Context("and the broker has no ClusterJoinRequest for the cluster", func() {
+			It("should not allocate a global CIDR", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				updated := t.getSubmariner(ctx)
+				Expect(updated.Status.GlobalCIDR).To(BeEmpty())
+				Expect(meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.BrokerCIDRAllocationFailedCondition)).To(BeNil())
+			})
+		})
+
+		Context("and globalnet is enabled on the broker", func() {
+			JustBeforeEach(func(ctx SpecContext) {
+				t.createJoinRequest(ctx)
+			})
+
+			It("should wait for the broker to allocate a global CIDR and record it in the status", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+				Expect(t.getSubmariner(ctx).Status.GlobalCIDR).To(BeEmpty())
+
+				t.processJoinRequest(ctx)
+				t.AssertReconcileSuccess(ctx)
+
+				updated := t.getSubmariner(ctx)
+				Expect(updated.Status.GlobalCIDR).To(Equal("242.0.0.0/16"))
+				Expect(updated.Spec.GlobalCIDR).To(BeEmpty())
+
+				t.submariner.Spec.GlobalCIDR = "242.0.0.0/16"
+				t.assertGlobalnetDaemonSet(ctx)
+			})
+
+			It("should keep the allocated global CIDR on subsequent reconciles", func(ctx SpecContext) {
+				t.processJoinRequest(ctx)
+
+				t.AssertReconcileSuccess(ctx)
+				t.AssertReconcileSuccess(ctx)
+
+				Expect(t.getSubmariner(ctx).Status.GlobalCIDR).To(Equal("242.0.0.0/16"))
+			})
+		})
+
+		Context("and a globalnet cluster size is requested", func() {
+			BeforeEach(func() {
+				t.submariner.Spec.GlobalnetClusterSize = 200
+			})
+
+			JustBeforeEach(func(ctx SpecContext) {
+				t.createJoinRequest(ctx)
+			})
+
+			It("should request it from the broker and record the global CIDR of the granted size", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				request := &v1alpha1.ClusterJoinRequest{}
+				Expect(t.brokerClient.Get(ctx, client.ObjectKey{
+					Namespace: t.submariner.Spec.BrokerK8sRemoteNamespace,
+					Name:      opnames.ForClusterJoinRequest(t.submariner.Spec.ClusterID),
+				}, request)).To(Succeed())
+				Expect(request.Spec.GlobalnetClusterSize).To(Equal(uint(200)))
+
+				t.processJoinRequest(ctx)
+				t.AssertReconcileSuccess(ctx)
+
+				updated := t.getSubmariner(ctx)
+				Expect(updated.Status.GlobalCIDR).To(Equal("242.0.0.0/24"))
+				Expect(updated.Status.GlobalnetClusterSize).To(Equal(uint(256)))
+			})
+
+			It("should not report the cluster size once the broker fails to grant a changed size", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+				t.processJoinRequest(ctx)
+				t.AssertReconcileSuccess(ctx)
+				Expect(t.getSubmariner(ctx).Status.GlobalnetClusterSize).To(Equal(uint(256)))
+
+				updated := t.getSubmariner(ctx)
+				updated.Spec.GlobalnetClusterSize = 1 << 24
+				Expect(t.ScopedClient.Update(ctx, updated)).To(Succeed())
+
+				t.AssertReconcileRequeue(ctx)
+				t.processJoinRequest(ctx)
+				t.AssertReconcileError(ctx)
+
+				updated = t.getSubmariner(ctx)
+				Expect(updated.Status.GlobalnetClusterSize).To(BeZero())
+
+				condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.BrokerCIDRAllocationFailedCondition)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("AllocationFailed"))
+			})
+		})
+
+		Context("and the requested globalnet cluster size exceeds the broker's range", func() {
+			BeforeEach(func() {
+				t.submariner.Spec.GlobalnetClusterSize = 1 << 24
+			})
+
+			JustBeforeEach(func(ctx SpecContext) {
+				t.createJoinRequest(ctx)
+			})
+
+			It("should not allocate a global CIDR and report the broker's failure", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+				t.processJoinRequest(ctx)
+				t.AssertReconcileError(ctx)
+
+				updated := t.getSubmariner(ctx)
+				Expect(updated.Status.GlobalCIDR).To(BeEmpty())
+				Expect(updated.Status.GlobalnetClusterSize).To(BeZero())
+
+				condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.BrokerCIDRAllocationFailedCondition)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("AllocationFailed"))
+				Expect(condition.Message).To(ContainSubstring("invalid cluster size"))
+			})
+		})
+
+		Context("and globalnet is disabled on the broker", func() {
+			BeforeEach(func() {
+				globalnetEnabled = false
+			})
+
+			JustBeforeEach(func(ctx SpecContext) {
+				t.createJoinRequest(ctx)
+				t.processJoinRequest(ctx)
+			})
+
+			reenableGlobalnet := func(ctx context.Context) {
+				Expect(t.brokerClient.DeleteAllOf(ctx, &corev1.ConfigMap{},
+					client.InNamespace(t.submariner.Spec.BrokerK8sRemoteNamespace))).To(Succeed())
+				Expect(globalnet.CreateConfigMap(ctx, t.brokerClient, true, globalnet.DefaultGlobalnetCIDR,
+					globalnet.DefaultGlobalnetClusterSize, t.submariner.Spec.BrokerK8sRemoteNamespace)).To(Succeed())
+
+				t.processJoinRequest(ctx)
+			}
+
+			It("should not allocate a global CIDR", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				Expect(t.getSubmariner(ctx).Status.GlobalCIDR).To(BeEmpty())
+				t.AssertNoDaemonSet(ctx, names.GlobalnetComponent)
+			})
+
+			It("should not check the broker again on subsequent reconciles", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				reenableGlobalnet(ctx)
+
+				t.AssertReconcileSuccess(ctx)
+				Expect(t.getSubmariner(ctx).Status.GlobalCIDR).To(BeEmpty())
+			})
+
+			Context("and the cluster then fails over to another broker endpoint", func() {
+				const secondaryBrokerAPIServer = "https://secondary-broker:6443"
+
+				var primaryAvailable bool
+
+				BeforeEach(func() {
+					primaryAvailable = true
+
+					t.submariner.Spec.BrokerK8sEndpoints = []v1alpha1.BrokerK8sEndpoint{{ApiServer: secondaryBrokerAPIServer}}
+
+					t.getAuthorizedBrokerClientFor = func(spec *v1alpha1.SubmarinerSpec, _, _ string, _ schema.GroupVersionResource,
+					) (dynamic.Interface, error) {
+						if spec.BrokerK8sApiServer != secondaryBrokerAPIServer && !primaryAvailable {
+							return nil, fmt.Errorf("broker %q is unavailable", spec.BrokerK8sApiServer)
+						}
+
+						return t.dynClient, nil
+					}
+				})
+
+				It("should check the broker again through that endpoint", func(ctx SpecContext) {
+					t.AssertReconcileRequeue(ctx)
+					Expect(t.getSubmariner(ctx).Status.GlobalCIDR).To(BeEmpty())
+
+					reenableGlobalnet(ctx)
+
+					primaryAvailable = false
+
+					t.AssertReconcileRequeue(ctx)
+					Expect(t.getSubmariner(ctx).Status.ActiveBrokerK8sApiServer).To(Equal(secondaryBrokerAPIServer))
+					Expect(t.getSubmariner(ctx).Status.GlobalCIDR).To(Equal("242.0.0.0/16"))
+				})
+			})
+		})
+	})
+
+	When("ClustersetIP is enabled and its CIDR isn't specified", func() {
+		BeforeEach(func() {
+			t.submariner.Spec.ClustersetIPEnabled = true
+		})
+
+		JustBeforeEach(func(ctx SpecContext) {
+			Expect(clustersetip.CreateConfigMap(ctx, t.brokerClient, true, clustersetip.DefaultCIDR, 0,
+				t.submariner.Spec.BrokerK8sRemoteNamespace)).To(Succeed())
+		})
+
+		It("should record the ClustersetIP CIDR allocated by the broker in the status", func(ctx SpecContext) {
+			t.createJoinRequest(ctx)
+			t.processJoinRequest(ctx)
+
+			t.AssertReconcileSuccess(ctx)
+
+			updated := t.getSubmariner(ctx)
+			Expect(updated.Status.ClustersetIPCIDR).To(Equal("243.0.0.0/20"))
+			Expect(updated.Spec.ClustersetIPCIDR).To(BeEmpty())
+		})
+
+		Context("and the broker has no ClusterJoinRequest for the cluster", func() {
+			It("should report the failure", func(ctx SpecContext) {
+				t.AssertReconcileError(ctx)
+
+				condition := meta.FindStatusCondition(t.getSubmariner(ctx).Status.Conditions,
+					v1alpha1.BrokerCIDRAllocationFailedCondition)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Reason).To(Equal("JoinRequestNotFound"))
+			})
 		})
 	})
 
@@ -154,6 +678,26 @@ func testReconciliation() {
 		})
 	})
 
+	When("network plugin settings are discovered", func() {
+		BeforeEach(func() {
+			t.clusterNetwork.NetworkPlugin = cni.Calico
+			t.clusterNetwork.PluginSettings = map[string]string{
+				network.CalicoIPIPModeSetting:  "Never",
+				network.CalicoVXLANModeSetting: "CrossSubnet",
+			}
+		})
+
+		It("should pass them to the route agent", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			Expect(t.getSubmariner(ctx).Status.NetworkDiscovery.PluginSettings).To(Equal(t.clusterNetwork.PluginSettings))
+
+			envMap := test.EnvMapFrom(t.AssertDaemonSet(ctx, names.RouteAgentComponent))
+			Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NETWORKPLUGIN_IPIPMODE", "Never"))
+			Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NETWORKPLUGIN_VXLANMODE", "CrossSubnet"))
+		})
+	})
+
 	When("the submariner route-agent DaemonSet already exists", func() {
 		BeforeEach(func() {
 			t.InitScopedClientObjs = append(t.InitScopedClientObjs, t.NewDaemonSet(names.RouteAgentComponent))
@@ -206,6 +750,22 @@ func testReconciliation() {
 		})
 	})
 
+	When("additional global CIDRs are specified", func() {
+		BeforeEach(func() {
+			t.submariner.Spec.AdditionalGlobalCIDRs = []string{"169.253.0.0/16", "169.252.0.0/16"}
+		})
+
+		It("should pass all the global CIDRs to the components", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			expected := "169.254.0.0/16,169.253.0.0/16,169.252.0.0/16"
+
+			for _, component := range []string{names.GatewayComponent, names.RouteAgentComponent, names.GlobalnetComponent} {
+				Expect(test.EnvMapFrom(t.AssertDaemonSet(ctx, component))).To(HaveKeyWithValue("SUBMARINER_GLOBALCIDR", expected))
+			}
+		})
+	})
+
 	When("ServiceDiscovery is enabled", func() {
 		BeforeEach(func() {
 			t.submariner.Spec.ServiceDiscoveryEnabled = true
@@ -318,6 +878,60 @@ func testReconciliation() {
 			})
 		})
 
+		It("should report the secret syncer status", func(ctx SpecContext) {
+			t.AssertReconcileSuccess(ctx)
+
+			syncerStatus := t.getSubmariner(ctx).Status.BrokerSecretSyncer
+			Expect(syncerStatus).ToNot(BeNil())
+			Expect(syncerStatus.BrokerK8sApiServer).To(Equal(t.submariner.Spec.BrokerK8sApiServer))
+			Expect(syncerStatus.BrokerK8sRemoteNamespace).To(Equal(t.submariner.Spec.BrokerK8sRemoteNamespace))
+			Expect(syncerStatus.StartTime).ToNot(BeNil())
+			Expect(syncerStatus.LastError).To(BeEmpty())
+
+			Eventually(func(ctx context.Context) *metav1.Time {
+				t.AssertReconcileSuccess(ctx)
+				return t.getSubmariner(ctx).Status.BrokerSecretSyncer.LastSyncTime
+			}).WithContext(ctx).ShouldNot(BeNil())
+		})
+
+		Context("and the broker remote namespace is subsequently changed", func() {
+			BeforeEach(func() {
+				// Once synced, the local secret's credentials are used to access the broker.
+				t.getAuthorizedBrokerClientFor = func(_ *v1alpha1.SubmarinerSpec, _, _ string, _ schema.GroupVersionResource,
+				) (dynamic.Interface, error) {
+					return t.dynClient, nil
+				}
+			})
+
+			It("should restart the secret syncer against the new namespace", func(ctx SpecContext) {
+				t.AssertReconcileSuccess(ctx)
+
+				ri := resource.ForDynamic(t.secrets.Namespace(t.submariner.Spec.Namespace))
+				testutil.AwaitResource(ri, t.submariner.Spec.BrokerK8sSecret)
+
+				submariner := t.getSubmariner(ctx)
+				submariner.Spec.BrokerK8sRemoteNamespace = "other-broker"
+				Expect(t.ScopedClient.Update(ctx, submariner)).To(Succeed())
+
+				t.AssertReconcileSuccess(ctx)
+
+				Expect(t.getSubmariner(ctx).Status.BrokerSecretSyncer.BrokerK8sRemoteNamespace).To(Equal("other-broker"))
+
+				// Updates in the old broker namespace must no longer be synced.
+				brokerSecret.Data = map[string][]byte{"data": {7, 8, 9}}
+				syncertest.UpdateResource(t.secrets.Namespace(brokerSecret.Namespace), brokerSecret)
+
+				newBrokerSecret := brokerSecret.DeepCopy()
+				newBrokerSecret.Namespace = "other-broker"
+				newBrokerSecret.Data = map[string][]byte{"data": {70, 80, 90}}
+				syncertest.CreateResource(t.secrets.Namespace(newBrokerSecret.Namespace), newBrokerSecret)
+
+				testutil.AwaitAndVerifyResource(ri, t.submariner.Spec.BrokerK8sSecret, func(obj *unstructured.Unstructured) bool {
+					return reflect.DeepEqual(resource.MustFromUnstructured(obj, &corev1.Secret{}).Data, newBrokerSecret.Data)
+				})
+			})
+		})
+
 		Context("and the local secret already exists", func() {
 			BeforeEach(func() {
 				t.submariner.Spec.BrokerK8sSecret = "submariner-broker-secret"
@@ -349,6 +963,93 @@ func testReconciliation() {
 		})
 	})
 
+	When("multiple broker endpoints are specified", func() {
+		const (
+			primaryBrokerAPIServer   = "https://primary-broker:6443"
+			secondaryBrokerAPIServer = "https://secondary-broker:6443"
+			secondaryBrokerCA        = "secondary-broker-ca"
+		)
+
+		var primaryAvailable bool
+
+		BeforeEach(func() {
+			primaryAvailable = false
+
+			t.submariner.Spec.BrokerK8sApiServer = primaryBrokerAPIServer
+			t.submariner.Spec.BrokerK8sEndpoints = []v1alpha1.BrokerK8sEndpoint{
+				{ApiServer: secondaryBrokerAPIServer, CA: secondaryBrokerCA},
+			}
+
+			t.getAuthorizedBrokerClientFor = func(spec *v1alpha1.SubmarinerSpec, _, brokerCA string, _ schema.GroupVersionResource,
+			) (dynamic.Interface, error) {
+				switch spec.BrokerK8sApiServer {
+				case primaryBrokerAPIServer:
+					if !primaryAvailable {
+						return nil, fmt.Errorf("broker %q is unavailable", spec.BrokerK8sApiServer)
+					}
+
+					Expect(brokerCA).To(Equal(t.submariner.Spec.BrokerK8sCA))
+				case secondaryBrokerAPIServer:
+					Expect(brokerCA).To(Equal(secondaryBrokerCA))
+				default:
+					Fail("Unexpected broker API server " + spec.BrokerK8sApiServer)
+				}
+
+				return t.dynClient, nil
+			}
+		})
+
+		It("should use the first available endpoint, starting with the base one, and periodically re-check", func(ctx SpecContext) {
+			t.AssertReconcileRequeue(ctx)
+
+			Expect(t.getSubmariner(ctx).Status.ActiveBrokerK8sApiServer).To(Equal(secondaryBrokerAPIServer))
+
+			envMap := test.EnvMapFrom(t.AssertDaemonSet(ctx, names.GatewayComponent))
+			Expect(envMap).To(HaveKeyWithValue(broker.EnvironmentVariable("ApiServer"), secondaryBrokerAPIServer))
+			Expect(envMap).To(HaveKeyWithValue(broker.EnvironmentVariable("CA"), secondaryBrokerCA))
+
+			primaryAvailable = true
+
+			t.AssertReconcileRequeue(ctx)
+
+			Expect(t.getSubmariner(ctx).Status.ActiveBrokerK8sApiServer).To(Equal(primaryBrokerAPIServer))
+
+			envMap = test.EnvMapFrom(t.AssertDaemonSet(ctx, names.GatewayComponent))
+			Expect(envMap).To(HaveKeyWithValue(broker.EnvironmentVariable("ApiServer"), primaryBrokerAPIServer))
+			Expect(envMap).To(HaveKeyWithValue(broker.EnvironmentVariable("CA"), t.submariner.Spec.BrokerK8sCA))
+		})
+
+		Context("and the base endpoint is also listed", func() {
+			BeforeEach(func() {
+				primaryAvailable = true
+
+				t.submariner.Spec.BrokerK8sEndpoints = append(t.submariner.Spec.BrokerK8sEndpoints,
+					v1alpha1.BrokerK8sEndpoint{ApiServer: primaryBrokerAPIServer})
+			})
+
+			It("should use it first", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				Expect(t.getSubmariner(ctx).Status.ActiveBrokerK8sApiServer).To(Equal(primaryBrokerAPIServer))
+			})
+		})
+
+		Context("and none are available", func() {
+			BeforeEach(func() {
+				t.getAuthorizedBrokerClientFor = func(spec *v1alpha1.SubmarinerSpec, _, _ string, _ schema.GroupVersionResource,
+				) (dynamic.Interface, error) {
+					return nil, fmt.Errorf("broker %q is unavailable", spec.BrokerK8sApiServer)
+				}
+			})
+
+			It("should keep using the base endpoint", func(ctx SpecContext) {
+				t.AssertReconcileRequeue(ctx)
+
+				Expect(t.getSubmariner(ctx).Status.ActiveBrokerK8sApiServer).To(Equal(primaryBrokerAPIServer))
+			})
+		})
+	})
+
 	When("the Submariner resource doesn't exist", func() {
 		BeforeEach(func() {
 			t.InitScopedClientObjs = nil
@@ -565,6 +1266,66 @@ func testDeletion() {
 		})
 	})
 
+	Context("and the cluster has CIDRs allocated on the broker", func() {
+		BeforeEach(func() {
+			t.submariner.Spec.GlobalCIDR = ""
+			t.submariner.Spec.Version = "devel"
+
+			t.InitScopedClientObjs = append(t.InitScopedClientObjs,
+				t.NewDaemonSet(names.GatewayComponent),
+				t.NewDaemonSet(names.RouteAgentComponent))
+		})
+
+		JustBeforeEach(func(ctx SpecContext) {
+			brokerNamespace := t.submariner.Spec.BrokerK8sRemoteNamespace
+
+			Expect(globalnet.CreateConfigMap(ctx, t.brokerClient, true, "169.254.0.0/16", 8192, brokerNamespace)).To(Succeed())
+			Expect(clustersetip.CreateConfigMap(ctx, t.brokerClient, true, "243.0.0.0/16", 4096, brokerNamespace)).To(Succeed())
+
+			for _, clusterID := range []string{t.submariner.Spec.ClusterID, "west"} {
+				Expect(globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, t.brokerClient, brokerNamespace,
+					&globalnet.Config{ClusterID: clusterID}, reporter.Silent())).To(Succeed())
+				Expect(clustersetip.AllocateCIDRFromConfigMap(ctx, t.brokerClient, brokerNamespace,
+					&clustersetip.Config{ClusterID: clusterID}, reporter.Silent())).To(BeTrue())
+			}
+
+			Expect(t.brokerClient.Create(ctx, &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+				Name:      opnames.ForClusterHeartbeat(t.submariner.Spec.ClusterID),
+				Namespace: brokerNamespace,
+				Labels:    map[string]string{federate.ClusterIDLabelKey: t.submariner.Spec.ClusterID},
+			}})).To(Succeed())
+		})
+
+		It("should release them and announce the cluster's departure", func(ctx SpecContext) {
+			t.AssertReconcileRequeue(ctx)
+
+			t.UpdateDaemonSetToReady(ctx, t.assertUninstallGatewayDaemonSet(ctx))
+			t.UpdateDaemonSetToReady(ctx, t.assertUninstallRouteAgentDaemonSet(ctx))
+
+			t.AssertReconcileSuccess(ctx)
+			t.awaitSubmarinerDeleted()
+
+			lease := &coordinationv1.Lease{}
+			Expect(t.brokerClient.Get(ctx, client.ObjectKey{
+				Namespace: t.submariner.Spec.BrokerK8sRemoteNamespace,
+				Name:      opnames.ForClusterHeartbeat(t.submariner.Spec.ClusterID),
+			}, lease)).To(Succeed())
+			Expect(lease.Annotations).To(HaveKey("submariner.io/departed"))
+			Expect(lease.Labels).To(HaveKeyWithValue(federate.ClusterIDLabelKey, t.submariner.Spec.ClusterID))
+
+			globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, t.brokerClient, t.submariner.Spec.BrokerK8sRemoteNamespace)
+			Expect(err).To(Succeed())
+			Expect(globalnetInfo.Clusters).ToNot(HaveKey(t.submariner.Spec.ClusterID))
+			Expect(globalnetInfo.Clusters).To(HaveKey("west"))
+
+			clustersetIPInfo, _, err := clustersetip.GetClustersetIPNetworks(ctx, t.brokerClient,
+				t.submariner.Spec.BrokerK8sRemoteNamespace)
+			Expect(err).To(Succeed())
+			Expect(clustersetIPInfo.Clusters).ToNot(HaveKey(t.submariner.Spec.ClusterID))
+			Expect(clustersetIPInfo.Clusters).To(HaveKey("west"))
+		})
+	})
+
 	Context("and an uninstall DaemonSet does not complete in time", func() {
 		BeforeEach(func() {
 			t.submariner.Spec.GlobalCIDR = ""
@@ -665,6 +1426,19 @@ func testDeletion() {
 	})
 }
 
+func newControlPlanePod(component, arg string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      component,
+			Labels:    map[string]string{"component": component},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: component, Command: []string{component, arg}}},
+		},
+	}
+}
+
 func newInfrastructureCluster(platformType v1config.PlatformType) *v1config.Infrastructure {
 	return &v1config.Infrastructure{
 		ObjectMeta: metav1.ObjectMeta{
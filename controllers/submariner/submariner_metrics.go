@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
 	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -32,6 +33,10 @@ const (
 	connectionsRemoteClusterLabel  = "remote_cluster"
 	connectionsRemoteHostnameLabel = "remote_hostname"
 	connectionsStatusLabel         = "status"
+	cidrPoolNamespaceLabel         = "namespace"
+	cidrPoolLabel                  = "pool"
+	secretSyncerNamespaceLabel     = "namespace"
+	secretSyncerNameLabel          = "name"
 )
 
 var (
@@ -64,10 +69,61 @@ var (
 			connectionsStatusLabel,
 		},
 	)
+	brokerSecretSyncerStartTimeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_secret_syncer_start_timestamp",
+			Help: "Timestamp at which the broker secret syncer was started",
+		},
+		[]string{secretSyncerNamespaceLabel, secretSyncerNameLabel},
+	)
+	brokerSecretSyncerLastSyncTimeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_secret_syncer_last_sync_timestamp",
+			Help: "Timestamp of the last successful broker secret sync",
+		},
+		[]string{secretSyncerNamespaceLabel, secretSyncerNameLabel},
+	)
+	brokerSecretSyncerLastErrorTimeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_secret_syncer_last_error_timestamp",
+			Help: "Timestamp of the last failed broker secret sync",
+		},
+		[]string{secretSyncerNamespaceLabel, secretSyncerNameLabel},
+	)
+	cidrPoolUsedAddressesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_cidr_pool_used_addresses",
+			Help: "Number of addresses allocated to clusters from a broker CIDR pool",
+		},
+		[]string{cidrPoolNamespaceLabel, cidrPoolLabel},
+	)
+	cidrPoolFreeAddressesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_cidr_pool_free_addresses",
+			Help: "Number of unallocated addresses in a broker CIDR pool",
+		},
+		[]string{cidrPoolNamespaceLabel, cidrPoolLabel},
+	)
+	cidrPoolAvailableAllocationsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_cidr_pool_available_allocations",
+			Help: "Number of further default-sized allocations which fit in a broker CIDR pool",
+		},
+		[]string{cidrPoolNamespaceLabel, cidrPoolLabel},
+	)
+	cidrPoolFragmentationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_broker_cidr_pool_fragmentation_ratio",
+			Help: "Fraction of the unallocated addresses in a broker CIDR pool which can't be used by default-sized allocations",
+		},
+		[]string{cidrPoolNamespaceLabel, cidrPoolLabel},
+	)
 )
 
 func init() {
-	metrics.Registry.MustRegister(gatewaysGauge, connectionsGauge, gatewayCreationTimeGauge)
+	metrics.Registry.MustRegister(gatewaysGauge, connectionsGauge, gatewayCreationTimeGauge, brokerSecretSyncerStartTimeGauge,
+		brokerSecretSyncerLastSyncTimeGauge, brokerSecretSyncerLastErrorTimeGauge, cidrPoolUsedAddressesGauge, cidrPoolFreeAddressesGauge,
+		cidrPoolAvailableAllocationsGauge, cidrPoolFragmentationGauge)
 }
 
 func recordGateways(count int) {
@@ -98,3 +154,49 @@ func recordConnection(localEndpoint, remoteEndpoint *submv1.EndpointSpec, status
 		connectionsStatusLabel:         status,
 	}).Inc()
 }
+
+func recordBrokerSecretSyncerStarted(namespace, name string, startTime time.Time) {
+	labels := secretSyncerLabels(namespace, name)
+
+	brokerSecretSyncerStartTimeGauge.With(labels).Set(float64(startTime.Unix()))
+	brokerSecretSyncerLastSyncTimeGauge.With(labels).Set(0)
+	brokerSecretSyncerLastErrorTimeGauge.With(labels).Set(0)
+}
+
+func recordBrokerSecretSyncerStopped(namespace, name string) {
+	labels := secretSyncerLabels(namespace, name)
+
+	brokerSecretSyncerStartTimeGauge.Delete(labels)
+	brokerSecretSyncerLastSyncTimeGauge.Delete(labels)
+	brokerSecretSyncerLastErrorTimeGauge.Delete(labels)
+}
+
+func recordBrokerSecretSyncerSync(namespace, name string, syncTime time.Time) {
+	brokerSecretSyncerLastSyncTimeGauge.With(secretSyncerLabels(namespace, name)).Set(float64(syncTime.Unix()))
+}
+
+func recordBrokerSecretSyncerError(namespace, name string, errorTime time.Time) {
+	brokerSecretSyncerLastErrorTimeGauge.With(secretSyncerLabels(namespace, name)).Set(float64(errorTime.Unix()))
+}
+
+func secretSyncerLabels(namespace, name string) prometheus.Labels {
+	return prometheus.Labels{secretSyncerNamespaceLabel: namespace, secretSyncerNameLabel: name}
+}
+
+func recordCIDRPool(namespace, pool string, utilization *cidr.Utilization) {
+	labels := prometheus.Labels{cidrPoolNamespaceLabel: namespace, cidrPoolLabel: pool}
+
+	cidrPoolUsedAddressesGauge.With(labels).Set(bigToFloat(utilization.UsedAddresses))
+	cidrPoolFreeAddressesGauge.With(labels).Set(bigToFloat(utilization.FreeAddresses))
+	cidrPoolAvailableAllocationsGauge.With(labels).Set(bigToFloat(utilization.AvailableAllocations))
+	cidrPoolFragmentationGauge.With(labels).Set(utilization.Fragmentation)
+}
+
+func clearCIDRPool(namespace, pool string) {
+	labels := prometheus.Labels{cidrPoolNamespaceLabel: namespace, cidrPoolLabel: pool}
+
+	cidrPoolUsedAddressesGauge.Delete(labels)
+	cidrPoolFreeAddressesGauge.Delete(labels)
+	cidrPoolAvailableAllocationsGauge.Delete(labels)
+	cidrPoolFragmentationGauge.Delete(labels)
+}
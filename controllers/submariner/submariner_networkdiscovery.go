@@ -21,6 +21,8 @@ package submariner
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -28,14 +30,24 @@ import (
 	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
 )
 
+// configuredCIDRsSource is the source reported for CIDRs configured in the Submariner spec.
+const configuredCIDRsSource = "Spec"
+
 func (r *Reconciler) getClusterNetwork(ctx context.Context, submariner *submopv1a1.Submariner) (*network.ClusterNetwork, error) {
 	const UnknownPlugin = "unknown"
 
-	// If a previously cached discovery exists, use that
+	// If a previously cached discovery exists, use that, unless it's due to be refreshed
 	if r.config.ClusterNetwork != nil && r.config.ClusterNetwork.NetworkPlugin != UnknownPlugin {
+		if r.networkRediscoveryDue(submariner) {
+			r.rediscoverNetwork(ctx, submariner)
+		}
+
 		return r.config.ClusterNetwork, nil
 	}
 
+	r.networkRediscoveryRequested.Store(false)
+	r.networkDiscoveredAt = time.Now()
+
 	clusterNetwork, err := network.Discover(ctx, r.config.GeneralClient, submariner.Namespace)
 	if err != nil {
 		log.Error(err, "Error trying to discover network")
@@ -58,29 +70,49 @@ func (r *Reconciler) getClusterNetwork(ctx context.Context, submariner *submopv1
 func (r *Reconciler) discoverNetwork(ctx context.Context, submariner *submopv1a1.Submariner, log logr.Logger,
 ) (*network.ClusterNetwork, error) {
 	clusterNetwork, err := r.getClusterNetwork(ctx, submariner)
-	submariner.Status.ClusterCIDR = getCIDR(
-		log,
-		"Cluster",
-		submariner.Spec.ClusterCIDR,
-		clusterNetwork.PodCIDRs)
-
-	submariner.Status.ServiceCIDR = getCIDR(
-		log,
-		"Service",
-		submariner.Spec.ServiceCIDR,
-		clusterNetwork.ServiceCIDRs)
+	configuredClusterCIDRs := configuredCIDRs(submariner.Spec.ClusterCIDRs, submariner.Spec.ClusterCIDR)
+	submariner.Status.ClusterCIDRs = getCIDRs(log, "Cluster", configuredClusterCIDRs, clusterNetwork.PodCIDRs)
+	submariner.Status.ClusterCIDR = firstCIDR(submariner.Status.ClusterCIDRs)
+
+	configuredServiceCIDRs := configuredCIDRs(submariner.Spec.ServiceCIDRs, submariner.Spec.ServiceCIDR)
+	submariner.Status.ServiceCIDRs = getCIDRs(log, "Service", configuredServiceCIDRs, clusterNetwork.ServiceCIDRs)
+	submariner.Status.ServiceCIDR = firstCIDR(submariner.Status.ServiceCIDRs)
 
 	submariner.Status.NetworkPlugin = clusterNetwork.NetworkPlugin
 
+	submariner.Status.NetworkDiscovery = &submopv1a1.NetworkDiscoveryStatus{
+		ClusterCIDRsSource:    cidrsSource(configuredClusterCIDRs, clusterNetwork.PodCIDRsSource),
+		ServiceCIDRsSource:    cidrsSource(configuredServiceCIDRs, clusterNetwork.ServiceCIDRsSource),
+		NetworkPluginEvidence: clusterNetwork.NetworkPluginEvidence,
+		PluginSettings:        clusterNetwork.PluginSettings,
+	}
+
+	if len(configuredClusterCIDRs) == 0 {
+		submariner.Status.NetworkDiscovery.ClusterCIDRsConfidence = string(clusterNetwork.PodCIDRsConfidence)
+	}
+
+	r.reconcileNetworkDrift(submariner)
+
 	return clusterNetwork, err
 }
 
-func getCIDR(log logr.Logger, cidrType, currentCIDR string, detectedCIDRs []string) string {
-	detected := getFirstCIDR(detectedCIDRs)
+// configuredCIDRs returns the CIDRs configured in the spec, either as a list or, in older resources, as a single CIDR.
+func configuredCIDRs(cidrs []string, cidr string) []string {
+	if len(cidrs) > 0 {
+		return cidrs
+	}
 
-	if currentCIDR == "" {
-		if detected != "" {
-			log.Info("Using detected CIDR", "type", cidrType, "CIDR", detected)
+	if cidr != "" {
+		return []string{cidr}
+	}
+
+	return nil
+}
+
+func getCIDRs(log logr.Logger, cidrType string, configured, detected []string) []string {
+	if len(configured) == 0 {
+		if len(detected) > 0 {
+			log.Info("Using detected CIDRs", "type", cidrType, "CIDRs", detected)
 		} else {
 			log.Info("No detected CIDR", "type", cidrType)
 		}
@@ -88,26 +120,28 @@ func getCIDR(log logr.Logger, cidrType, currentCIDR string, detectedCIDRs []stri
 		return detected
 	}
 
-	if detected != "" && detected != currentCIDR {
+	if len(detected) > 0 && !slices.Equal(detected, configured) {
 		log.Error(
 			fmt.Errorf("there is a mismatch between the detected and configured CIDRs"),
-			"The configured CIDR will take precedence",
-			"type", cidrType, "configured", currentCIDR, "detected", detected)
+			"The configured CIDRs will take precedence",
+			"type", cidrType, "configured", configured, "detected", detected)
 	}
 
-	return currentCIDR
+	return configured
 }
 
-func getFirstCIDR(detectedCIDRs []string) string {
-	CIDRlen := len(detectedCIDRs)
-
-	if CIDRlen > 1 {
-		log.Error(fmt.Errorf("detected > 1 CIDRs"),
-			"we currently support only one", "detectedCIDRs", detectedCIDRs)
+// cidrsSource returns where the CIDRs in use came from; configured CIDRs take precedence over discovered ones.
+func cidrsSource(configured []string, discovered network.Source) string {
+	if len(configured) > 0 {
+		return configuredCIDRsSource
 	}
 
-	if CIDRlen > 0 {
-		return detectedCIDRs[0]
+	return string(discovered)
+}
+
+func firstCIDR(cidrs []string) string {
+	if len(cidrs) > 0 {
+		return cidrs[0]
 	}
 
 	return ""
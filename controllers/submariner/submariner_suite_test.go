@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -29,22 +30,28 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/submariner-io/admiral/pkg/log/kzerolog"
 	"github.com/submariner-io/admiral/pkg/names"
+	"github.com/submariner-io/admiral/pkg/reporter"
 	"github.com/submariner-io/admiral/pkg/syncer/broker"
 	"github.com/submariner-io/submariner-operator/api/v1alpha1"
 	submarinerController "github.com/submariner-io/submariner-operator/controllers/submariner"
 	"github.com/submariner-io/submariner-operator/controllers/test"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/clustersetip"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
 	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
 	opnames "github.com/submariner-io/submariner-operator/pkg/names"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = BeforeSuite(func() {
@@ -69,6 +76,9 @@ type testDriver struct {
 	clusterNetwork               *network.ClusterNetwork
 	dynClient                    *dynamicfake.FakeDynamicClient
 	secrets                      dynamic.NamespaceableResourceInterface
+	brokerClient                 controllerClient.Client
+	brokerControllerClientsBuilt int
+	eventRecorder                *record.FakeRecorder
 	getAuthorizedBrokerClientFor func(*v1alpha1.SubmarinerSpec, string, string, schema.GroupVersionResource) (dynamic.Interface, error)
 }
 
@@ -86,9 +96,13 @@ func newTestDriver() *testDriver {
 		t.InitScopedClientObjs = []controllerClient.Object{t.submariner}
 
 		t.clusterNetwork = &network.ClusterNetwork{
-			NetworkPlugin: "fake",
-			ServiceCIDRs:  []string{testDetectedServiceCIDR},
-			PodCIDRs:      []string{testDetectedClusterCIDR},
+			NetworkPlugin:         "fake",
+			NetworkPluginEvidence: "Pod kube-system/fake",
+			ServiceCIDRs:          []string{testDetectedServiceCIDR},
+			ServiceCIDRsSource:    network.SourceServiceCIDR,
+			PodCIDRs:              []string{testDetectedClusterCIDR},
+			PodCIDRsSource:        network.SourceNodeSpec,
+			PodCIDRsConfidence:    network.ConfidenceMedium,
 		}
 
 		t.dynClient = dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
@@ -96,6 +110,15 @@ func newTestDriver() *testDriver {
 			Version:  "v1",
 			Resource: "secrets",
 		})
+
+		t.brokerClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		t.brokerControllerClientsBuilt = 0
+		t.getAuthorizedBrokerClientFor = func(_ *v1alpha1.SubmarinerSpec, _, _ string, _ schema.GroupVersionResource,
+		) (dynamic.Interface, error) {
+			return t.dynClient, nil
+		}
+		// The recorder blocks once its buffer is full, so it must be large enough for all the events a test doesn't read.
+		t.eventRecorder = record.NewFakeRecorder(100)
 	})
 
 	JustBeforeEach(func() {
@@ -107,7 +130,13 @@ func newTestDriver() *testDriver {
 			DynClient:                    t.dynClient,
 			Scheme:                       scheme.Scheme,
 			ClusterNetwork:               t.clusterNetwork,
+			EventRecorder:                t.eventRecorder,
 			GetAuthorizedBrokerClientFor: t.getAuthorizedBrokerClientFor,
+			GetAuthorizedBrokerControllerClientFor: func(_ *v1alpha1.SubmarinerSpec, _, _ string, _ schema.GroupVersionResource,
+			) (controllerClient.Client, error) {
+				t.brokerControllerClientsBuilt++
+				return t.brokerClient, nil
+			},
 		})
 	})
 
@@ -122,6 +151,65 @@ func (t *testDriver) awaitSubmarinerDeleted() {
 	t.AwaitNoResource(t.submariner)
 }
 
+// createJoinRequest creates the cluster's ClusterJoinRequest on the broker, as the broker does for every member cluster.
+func (t *testDriver) createJoinRequest(ctx context.Context) {
+	Expect(t.brokerClient.Create(ctx, &v1alpha1.ClusterJoinRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opnames.ForClusterJoinRequest(t.submariner.Spec.ClusterID),
+			Namespace: t.submariner.Spec.BrokerK8sRemoteNamespace,
+		},
+		Spec: v1alpha1.ClusterJoinRequestSpec{ClusterID: t.submariner.Spec.ClusterID},
+	})).To(Succeed())
+}
+
+// processJoinRequest allocates the CIDRs requested by the cluster's ClusterJoinRequest from the broker's pools, and
+// reports the outcome in the request's status, as the broker does.
+func (t *testDriver) processJoinRequest(ctx context.Context) {
+	namespace := t.submariner.Spec.BrokerK8sRemoteNamespace
+
+	request := &v1alpha1.ClusterJoinRequest{}
+	Expect(t.brokerClient.Get(ctx, controllerClient.ObjectKey{
+		Namespace: namespace,
+		Name:      opnames.ForClusterJoinRequest(t.submariner.Spec.ClusterID),
+	}, request)).To(Succeed())
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ClusterJoinRequestReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "AwaitingToken",
+		ObservedGeneration: request.Generation,
+	}
+
+	var err error
+
+	if _, getErr := globalnet.GetConfigMap(ctx, t.brokerClient, namespace); getErr == nil {
+		globalnetConfig := &globalnet.Config{
+			ClusterID:   request.Spec.ClusterID,
+			ClusterSize: request.Spec.GlobalnetClusterSize,
+		}
+
+		err = globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, t.brokerClient, namespace, globalnetConfig, reporter.Silent())
+		request.Status.GlobalCIDR = globalnetConfig.GlobalCIDR
+		request.Status.AdditionalGlobalCIDRs = globalnetConfig.AdditionalGlobalCIDRs
+	}
+
+	if _, getErr := clustersetip.GetConfigMap(ctx, t.brokerClient, namespace); getErr == nil && err == nil {
+		clustersetIPConfig := &clustersetip.Config{ClusterID: request.Spec.ClusterID}
+
+		_, err = clustersetip.AllocateCIDRFromConfigMap(ctx, t.brokerClient, namespace, clustersetIPConfig, reporter.Silent())
+		request.Status.ClustersetIPCIDR = clustersetIPConfig.ClustersetIPCIDR
+	}
+
+	if err != nil {
+		condition.Reason = "AllocationFailed"
+		condition.Message = err.Error()
+	}
+
+	meta.SetStatusCondition(&request.Status.Conditions, condition)
+
+	Expect(t.brokerClient.Update(ctx, request)).To(Succeed())
+}
+
 func (t *testDriver) getSubmariner(ctx context.Context) *v1alpha1.Submariner {
 	obj := &v1alpha1.Submariner{}
 	err := t.ScopedClient.Get(ctx, types.NamespacedName{Name: submarinerName, Namespace: submarinerNamespace}, obj)
@@ -153,8 +241,8 @@ func (t *testDriver) assertUninstallRouteAgentDaemonSet(ctx context.Context) *ap
 func (t *testDriver) assertRouteAgentDaemonSetEnv(submariner *v1alpha1.Submariner, envMap map[string]string) {
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NAMESPACE", submariner.Spec.Namespace))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERID", submariner.Spec.ClusterID))
-	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERCIDR", submariner.Status.ClusterCIDR))
-	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_SERVICECIDR", submariner.Status.ServiceCIDR))
+	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERCIDR", strings.Join(submariner.Status.ClusterCIDRs, ",")))
+	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_SERVICECIDR", strings.Join(submariner.Status.ServiceCIDRs, ",")))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NETWORKPLUGIN", submariner.Status.NetworkPlugin))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_DEBUG", strconv.FormatBool(submariner.Spec.Debug)))
 }
@@ -194,8 +282,8 @@ func (t *testDriver) assertGatewayDaemonSetEnv(submariner *v1alpha1.Submariner,
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NATENABLED", strconv.FormatBool(submariner.Spec.
 		NatEnabled)))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERID", submariner.Spec.ClusterID))
-	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_SERVICECIDR", submariner.Status.ServiceCIDR))
-	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERCIDR", submariner.Status.ClusterCIDR))
+	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_SERVICECIDR", strings.Join(submariner.Status.ServiceCIDRs, ",")))
+	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERCIDR", strings.Join(submariner.Status.ClusterCIDRs, ",")))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_GLOBALCIDR", submariner.Spec.GlobalCIDR))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NAMESPACE", submariner.Spec.Namespace))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_DEBUG", strconv.FormatBool(submariner.Spec.Debug)))
@@ -226,6 +314,7 @@ func (t *testDriver) assertUninstallGlobalnetDaemonSet(ctx context.Context) *app
 func (t *testDriver) assertGlobalnetDaemonSetEnv(submariner *v1alpha1.Submariner, envMap map[string]string) {
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_NAMESPACE", submariner.Spec.Namespace))
 	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_CLUSTERID", submariner.Spec.ClusterID))
+	Expect(envMap).To(HaveKeyWithValue("SUBMARINER_GLOBALCIDR", submariner.Spec.GlobalCIDR))
 }
 
 func assertGatewayNodeSelector(daemonSet *appsv1.DaemonSet) {
@@ -233,8 +322,10 @@ func assertGatewayNodeSelector(daemonSet *appsv1.DaemonSet) {
 }
 
 func (t *testDriver) withNetworkDiscovery() *v1alpha1.Submariner {
-	t.submariner.Status.ClusterCIDR = getClusterCIDR(t.submariner, t.clusterNetwork)
-	t.submariner.Status.ServiceCIDR = getServiceCIDR(t.submariner, t.clusterNetwork)
+	t.submariner.Status.ClusterCIDRs = getClusterCIDRs(t.submariner, t.clusterNetwork)
+	t.submariner.Status.ClusterCIDR = t.submariner.Status.ClusterCIDRs[0]
+	t.submariner.Status.ServiceCIDRs = getServiceCIDRs(t.submariner, t.clusterNetwork)
+	t.submariner.Status.ServiceCIDR = t.submariner.Status.ServiceCIDRs[0]
 	t.submariner.Status.GlobalCIDR = getGlobalCIDR(t.submariner, t.clusterNetwork)
 	t.submariner.Status.ClustersetIPCIDR = getClustersetIPCIDR(t.submariner, t.clusterNetwork)
 	t.submariner.Status.NetworkPlugin = t.clusterNetwork.NetworkPlugin
@@ -271,20 +362,28 @@ func newSubmariner() *v1alpha1.Submariner {
 	}
 }
 
-func getClusterCIDR(submariner *v1alpha1.Submariner, clusterNetwork *network.ClusterNetwork) string {
+func getClusterCIDRs(submariner *v1alpha1.Submariner, clusterNetwork *network.ClusterNetwork) []string {
+	if len(submariner.Spec.ClusterCIDRs) > 0 {
+		return submariner.Spec.ClusterCIDRs
+	}
+
 	if submariner.Spec.ClusterCIDR != "" {
-		return submariner.Spec.ClusterCIDR
+		return []string{submariner.Spec.ClusterCIDR}
 	}
 
-	return clusterNetwork.PodCIDRs[0]
+	return clusterNetwork.PodCIDRs
 }
 
-func getServiceCIDR(submariner *v1alpha1.Submariner, clusterNetwork *network.ClusterNetwork) string {
+func getServiceCIDRs(submariner *v1alpha1.Submariner, clusterNetwork *network.ClusterNetwork) []string {
+	if len(submariner.Spec.ServiceCIDRs) > 0 {
+		return submariner.Spec.ServiceCIDRs
+	}
+
 	if submariner.Spec.ServiceCIDR != "" {
-		return submariner.Spec.ServiceCIDR
+		return []string{submariner.Spec.ServiceCIDR}
 	}
 
-	return clusterNetwork.ServiceCIDRs[0]
+	return clusterNetwork.ServiceCIDRs
 }
 
 func getGlobalCIDR(submariner *v1alpha1.Submariner, clusterNetwork *network.ClusterNetwork) string {
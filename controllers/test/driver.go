@@ -72,13 +72,13 @@ func (d *Driver) JustBeforeEach() {
 
 func (d *Driver) NewScopedClient() client.Client {
 	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(d.InitScopedClientObjs...).
-		WithStatusSubresource(&v1alpha1.Submariner{}).WithInterceptorFuncs(d.InterceptorFuncs).
+		WithStatusSubresource(&v1alpha1.Submariner{}, &v1alpha1.Broker{}, &v1alpha1.ClusterJoinRequest{}).WithInterceptorFuncs(d.InterceptorFuncs).
 		WithRESTMapper(test.GetRESTMapperFor(&corev1.Secret{})).Build()
 }
 
 func (d *Driver) NewGeneralClient() client.Client {
 	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(d.InitGeneralClientObjs...).
-		WithStatusSubresource(&v1alpha1.Submariner{}).WithInterceptorFuncs(d.InterceptorFuncs).Build()
+		WithStatusSubresource(&v1alpha1.Submariner{}, &v1alpha1.Broker{}, &v1alpha1.ClusterJoinRequest{}).WithInterceptorFuncs(d.InterceptorFuncs).Build()
 }
 
 func (d *Driver) DoReconcile(ctx context.Context) (reconcile.Result, error) {
@@ -0,0 +1,151 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// NewServiceAccountClient returns a client which accesses the objects of the given client as the given ServiceAccount,
+// authorizing each request against the Roles bound to the ServiceAccount in its namespace at the time of the request.
+// Cluster-wide authorization isn't supported; the ServiceAccount is only allowed to access namespaced objects.
+func NewServiceAccountClient(c client.WithWatch, namespace, saName string) client.Client {
+	authorize := func(ctx context.Context, verb string, obj runtime.Object, objNamespace, name string) error {
+		gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+		Expect(err).To(Succeed())
+
+		gvk.Kind, _ = strings.CutSuffix(gvk.Kind, "List")
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+		if objNamespace == namespace && serviceAccountIsAllowed(ctx, c, namespace, saName, verb, gvr, name) {
+			return nil
+		}
+
+		return apierrors.NewForbidden(gvr.GroupResource(), name, fmt.Errorf("ServiceAccount %q cannot %s", saName, verb))
+	}
+
+	return interceptor.NewClient(c, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := authorize(ctx, "get", obj, key.Namespace, key.Name); err != nil {
+				return err
+			}
+
+			return c.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			listOptions := &client.ListOptions{}
+			listOptions.ApplyOptions(opts)
+
+			if err := authorize(ctx, "list", list, listOptions.Namespace, ""); err != nil {
+				return err
+			}
+
+			return c.List(ctx, list, opts...)
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			// Creation can't be restricted by resource name.
+			if err := authorize(ctx, "create", obj, obj.GetNamespace(), ""); err != nil {
+				return err
+			}
+
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := authorize(ctx, "update", obj, obj.GetNamespace(), obj.GetName()); err != nil {
+				return err
+			}
+
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := authorize(ctx, "patch", obj, obj.GetNamespace(), obj.GetName()); err != nil {
+				return err
+			}
+
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if err := authorize(ctx, "delete", obj, obj.GetNamespace(), obj.GetName()); err != nil {
+				return err
+			}
+
+			return c.Delete(ctx, obj, opts...)
+		},
+	})
+}
+
+func serviceAccountIsAllowed(ctx context.Context, c client.Client, namespace, saName, verb string, gvr schema.GroupVersionResource,
+	name string,
+) bool {
+	roleBindings := &rbacv1.RoleBindingList{}
+	Expect(c.List(ctx, roleBindings, client.InNamespace(namespace))).To(Succeed())
+
+	for i := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[i]
+
+		if roleBinding.RoleRef.Kind != "Role" || !slices.ContainsFunc(roleBinding.Subjects, func(subject rbacv1.Subject) bool {
+			return subject.Kind == rbacv1.ServiceAccountKind && subject.Name == saName && subject.Namespace == namespace
+		}) {
+			continue
+		}
+
+		role := &rbacv1.Role{}
+
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: roleBinding.RoleRef.Name}, role)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+
+		Expect(err).To(Succeed())
+
+		if slices.ContainsFunc(role.Rules, func(rule rbacv1.PolicyRule) bool {
+			return ruleAllows(&rule, verb, gvr, name)
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ruleAllows(rule *rbacv1.PolicyRule, verb string, gvr schema.GroupVersionResource, name string) bool {
+	matches := func(values []string, value string) bool {
+		return slices.Contains(values, rbacv1.ResourceAll) || slices.Contains(values, value)
+	}
+
+	if !matches(rule.Verbs, verb) || !matches(rule.APIGroups, gvr.Group) || !matches(rule.Resources, gvr.Resource) {
+		return false
+	}
+
+	// Rules restricted to resource names never apply to requests without a name, such as creation or listing.
+	return len(rule.ResourceNames) == 0 || (name != "" && slices.Contains(rule.ResourceNames, name))
+}
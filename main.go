@@ -27,6 +27,7 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/operator-framework/operator-lib/leader"
+	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log/kzerolog"
 	"github.com/submariner-io/admiral/pkg/names"
 	admversion "github.com/submariner-io/admiral/pkg/version"
@@ -34,8 +35,9 @@ import (
 	"github.com/submariner-io/submariner-operator/controllers/metrics"
 	"github.com/submariner-io/submariner-operator/controllers/servicediscovery"
 	"github.com/submariner-io/submariner-operator/controllers/submariner"
+	"github.com/submariner-io/submariner-operator/pkg/broker"
 	"github.com/submariner-io/submariner-operator/pkg/crd"
-	"github.com/submariner-io/submariner-operator/pkg/gateway"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
 	"github.com/submariner-io/submariner-operator/pkg/lighthouse"
 	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -62,11 +64,12 @@ var (
 )
 
 var (
-	scheme      = apiruntime.NewScheme()
-	log         = logf.Log.WithName("cmd")
-	help        = false
-	version     = "devel"
-	showVersion = false
+	scheme         = apiruntime.NewScheme()
+	log            = logf.Log.WithName("cmd")
+	help           = false
+	version        = "devel"
+	showVersion    = false
+	discoverFormat = ""
 )
 
 func printVersion() {
@@ -77,6 +80,8 @@ func printVersion() {
 func init() {
 	flag.BoolVar(&help, "help", help, "Print usage options")
 	flag.BoolVar(&showVersion, "version", showVersion, "Show version")
+	flag.StringVar(&discoverFormat, "discover", discoverFormat,
+		"Discover the cluster network, print it in the given format (json or yaml) and exit")
 }
 
 //nolint:gocyclo // No further refactors necessary
@@ -98,6 +103,15 @@ func main() {
 		return
 	}
 
+	if discoverFormat != "" {
+		if err := discoverNetwork(discoverFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	admversion.Print(names.OperatorComponent, version)
 
 	if showVersion {
@@ -144,9 +158,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	log.Info("Creating the Gateway CRDs")
+	log.Info("Creating the broker CRDs")
 
-	if err := gateway.Ensure(ctx, crdUpdater); err != nil {
+	if err := broker.Ensure(ctx, crdUpdater); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
@@ -209,25 +223,27 @@ func main() {
 
 	log.Info("Registering Components.")
 
+	generalClient, _ := client.New(mgr.GetConfig(), client.Options{
+		Scheme: scheme,
+	})
+
 	// Setup all Controllers
 	if err = (&submariner.BrokerReconciler{
-		Client: mgr.GetClient(),
-		Config: mgr.GetConfig(),
+		Client:           mgr.GetClient(),
+		AllocationClient: generalClient,
+		Config:           mgr.GetConfig(),
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "Broker")
 		os.Exit(1)
 	}
 
-	generalClient, _ := client.New(mgr.GetConfig(), client.Options{
-		Scheme: scheme,
-	})
-
 	if err = submariner.NewReconciler(&submariner.Config{
 		ScopedClient:  mgr.GetClient(),
 		GeneralClient: generalClient,
 		RestConfig:    mgr.GetConfig(),
 		Scheme:        mgr.GetScheme(),
 		DynClient:     dynamic.NewForConfigOrDie(mgr.GetConfig()),
+		EventRecorder: mgr.GetEventRecorderFor("submariner-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "Submariner")
 		os.Exit(1)
@@ -264,6 +280,28 @@ func main() {
 	}
 }
 
+// discoverNetwork prints the discovered cluster network, so that discovery can be checked without deploying Submariner.
+func discoverNetwork(format string) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "error getting the Kubernetes configuration")
+	}
+
+	discoveryClient, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return errors.Wrap(err, "error creating the Kubernetes client")
+	}
+
+	clusterNetwork, err := network.Discover(context.TODO(), discoveryClient, os.Getenv("WATCH_NAMESPACE"))
+	if clusterNetwork != nil {
+		if showErr := clusterNetwork.ShowAs(format); showErr != nil {
+			return showErr //nolint:wrapcheck // No need to wrap
+		}
+	}
+
+	return errors.Wrap(err, "error discovering the cluster network")
+}
+
 // getWatchNamespace returns the Namespace the operator should be watching for changes.
 func getWatchNamespace() (string, error) {
 	// WatchNamespaceEnvVar is the constant for env variable WATCH_NAMESPACE
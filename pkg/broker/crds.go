@@ -0,0 +1,42 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/pkg/crd"
+	"github.com/submariner-io/submariner-operator/pkg/embeddedyamls"
+)
+
+// Ensure ensures that the CRDs the broker controller relies on are deployed on the target system.
+// The resources handled here are the broker CRDs: ClusterJoinRequest and CIDRAllocation.
+func Ensure(ctx context.Context, crdUpdater crd.Updater) error {
+	_, err := crdUpdater.CreateOrUpdateFromEmbedded(ctx,
+		embeddedyamls.Deploy_crds_submariner_io_clusterjoinrequests_yaml)
+	if err != nil {
+		return errors.Wrap(err, "error provisioning the ClusterJoinRequest CRD")
+	}
+
+	_, err = crdUpdater.CreateOrUpdateFromEmbedded(ctx,
+		embeddedyamls.Deploy_crds_submariner_io_cidrallocations_yaml)
+
+	return errors.Wrap(err, "error provisioning the CIDRAllocation CRD")
+}
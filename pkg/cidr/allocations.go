@@ -0,0 +1,249 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cidr
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/names"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PoolLabel is set on CIDRAllocations to the pool they're allocated from.
+	PoolLabel = "submariner.io/cidr-pool"
+
+	// AllocationGenerationAnnotation is incremented on a pool's ConfigMap by each allocation, so that concurrent
+	// allocations from the pool conflict.
+	AllocationGenerationAnnotation = "submariner.io/allocation-generation"
+)
+
+// AllocationBackoff is used to retry allocations which conflict with concurrent ones. Each round of conflicting
+// allocations lets at least one through, so it allows for many retries.
+var AllocationBackoff = wait.Backoff{
+	Steps:    100,
+	Duration: 10 * time.Millisecond,
+	Factor:   1.2,
+	Jitter:   1.0,
+	Cap:      time.Second,
+}
+
+// GetAllocations returns the CIDRs allocated from the given pool, keyed by cluster ID. Allocations which haven't been
+// migrated from the pool's ConfigMap yet are included.
+func GetAllocations(ctx context.Context, client controllerClient.Client, configMap *corev1.ConfigMap, pool string,
+) (map[string]*ClusterInfo, error) {
+	clusters, err := ExtractClusterInfo(configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := &v1alpha1.CIDRAllocationList{}
+
+	err = client.List(ctx, allocations, controllerClient.InNamespace(configMap.Namespace),
+		controllerClient.MatchingLabels{PoolLabel: pool})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the %s CIDRAllocations", pool)
+	}
+
+	for i := range allocations.Items {
+		spec := &allocations.Items[i].Spec
+		clusters[spec.ClusterID] = &ClusterInfo{
+			ClusterID: spec.ClusterID,
+			CIDRs:     slices.Clone(spec.CIDRs),
+		}
+	}
+
+	return clusters, nil
+}
+
+// SetAllocation records the CIDRs allocated to a cluster from the given pool. A conflict error is returned if the
+// cluster's allocation is concurrently updated.
+func SetAllocation(ctx context.Context, client controllerClient.Client, namespace, pool string, info ClusterInfo) error {
+	allocation := &v1alpha1.CIDRAllocation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.ForCIDRAllocation(pool, info.ClusterID),
+			Namespace: namespace,
+			Labels: map[string]string{
+				PoolLabel:                  pool,
+				federate.ClusterIDLabelKey: info.ClusterID,
+			},
+		},
+		Spec: v1alpha1.CIDRAllocationSpec{
+			ClusterID: info.ClusterID,
+			Pool:      pool,
+			CIDRs:     info.CIDRs,
+		},
+	}
+
+	err := client.Create(ctx, allocation)
+	if !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "error creating CIDRAllocation %q", allocation.Name)
+	}
+
+	existing := &v1alpha1.CIDRAllocation{}
+
+	if err := client.Get(ctx, controllerClient.ObjectKeyFromObject(allocation), existing); err != nil {
+		return errors.Wrapf(err, "error retrieving CIDRAllocation %q", allocation.Name)
+	}
+
+	existing.Spec = allocation.Spec
+
+	return errors.Wrapf(client.Update(ctx, existing), "error updating CIDRAllocation %q", allocation.Name)
+}
+
+// CommitAllocation records the CIDRs allocated to a cluster from the pool whose ConfigMap is given, and increments the
+// ConfigMap's allocation generation. The ConfigMap must have been retrieved before the allocations the CIDRs were
+// checked against, and neither may come from a cache. If another allocation was committed in the meantime, the
+// cluster's allocation is rolled back and a conflict error is returned; the allocation must then be retried against
+// the latest allocations.
+func CommitAllocation(ctx context.Context, client controllerClient.Client, configMap *corev1.ConfigMap, pool string,
+	info ClusterInfo,
+) error {
+	previous := &v1alpha1.CIDRAllocation{}
+
+	err := client.Get(ctx, controllerClient.ObjectKey{
+		Namespace: configMap.Namespace,
+		Name:      names.ForCIDRAllocation(pool, info.ClusterID),
+	}, previous)
+	if apierrors.IsNotFound(err) {
+		previous = nil
+	} else if err != nil {
+		return errors.Wrapf(err, "error retrieving the %s CIDRAllocation of cluster %q", pool, info.ClusterID)
+	}
+
+	if err := SetAllocation(ctx, client, configMap.Namespace, pool, info); err != nil {
+		return err
+	}
+
+	generation, _ := strconv.ParseUint(configMap.Annotations[AllocationGenerationAnnotation], 10, 64)
+	metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, AllocationGenerationAnnotation, strconv.FormatUint(generation+1, 10))
+
+	err = client.Update(ctx, configMap)
+	if err == nil {
+		return nil
+	}
+
+	// The allocation may overlap with the one committed concurrently.
+	var rollbackErr error
+
+	if previous == nil {
+		_, rollbackErr = RemoveAllocation(ctx, client, configMap.Namespace, pool, info.ClusterID)
+	} else {
+		rollbackErr = SetAllocation(ctx, client, configMap.Namespace, pool, ClusterInfo{
+			ClusterID: info.ClusterID,
+			CIDRs:     previous.Spec.CIDRs,
+		})
+	}
+
+	if rollbackErr != nil {
+		return errors.Wrapf(rollbackErr, "error rolling back the %s allocation of cluster %q after %v", pool, info.ClusterID, err)
+	}
+
+	return errors.Wrapf(err, "error updating ConfigMap %q", configMap.Name)
+}
+
+// RemoveAllocation removes the CIDRs allocated to a cluster from the given pool, returning false if there were none.
+func RemoveAllocation(ctx context.Context, client controllerClient.Client, namespace, pool, clusterID string) (bool, error) {
+	err := client.Delete(ctx, &v1alpha1.CIDRAllocation{ObjectMeta: metav1.ObjectMeta{
+		Name:      names.ForCIDRAllocation(pool, clusterID),
+		Namespace: namespace,
+	}})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, errors.Wrapf(err, "error deleting the %s CIDRAllocation of cluster %q", pool, clusterID)
+}
+
+// ReleaseAllocation releases the CIDRs allocated to a cluster from the pool whose ConfigMap is given, including those
+// still recorded in the ConfigMap itself, returning false if there were none. The ConfigMap may be nil if the pool
+// doesn't have one. A conflict error is returned if the ConfigMap was updated concurrently.
+func ReleaseAllocation(ctx context.Context, client controllerClient.Client, namespace string, configMap *corev1.ConfigMap,
+	pool, clusterID string,
+) (bool, error) {
+	released, err := RemoveAllocation(ctx, client, namespace, pool, clusterID)
+	if err != nil || configMap == nil {
+		return released, err
+	}
+
+	clusterInfo, err := unmarshalClusterInfo(configMap)
+	if err != nil {
+		return released, err
+	}
+
+	count := len(clusterInfo)
+
+	clusterInfo = slices.DeleteFunc(clusterInfo, func(info ClusterInfo) bool {
+		return info.ClusterID == clusterID
+	})
+
+	if len(clusterInfo) == count {
+		return released, nil
+	}
+
+	data, err := json.MarshalIndent(clusterInfo, "", "\t")
+	if err != nil {
+		return released, errors.Wrap(err, "error marshalling ClusterInfo")
+	}
+
+	configMap.Data[ClusterInfoKey] = string(data)
+
+	return true, errors.Wrapf(client.Update(ctx, configMap), "error updating ConfigMap %q", configMap.Name)
+}
+
+// MigrateAllocations moves the allocations recorded in the given pool ConfigMap to CIDRAllocations. Existing
+// CIDRAllocations take precedence over the ConfigMap's data.
+func MigrateAllocations(ctx context.Context, client controllerClient.Client, configMap *corev1.ConfigMap, pool string) error {
+	clusterInfo, err := unmarshalClusterInfo(configMap)
+	if err != nil || len(clusterInfo) == 0 {
+		return err
+	}
+
+	for _, info := range clusterInfo {
+		name := names.ForCIDRAllocation(pool, info.ClusterID)
+
+		err := client.Get(ctx, controllerClient.ObjectKey{Namespace: configMap.Namespace, Name: name}, &v1alpha1.CIDRAllocation{})
+		if err == nil {
+			continue
+		}
+
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error retrieving CIDRAllocation %q", name)
+		}
+
+		if err := SetAllocation(ctx, client, configMap.Namespace, pool, info); err != nil {
+			return err
+		}
+	}
+
+	delete(configMap.Data, ClusterInfoKey)
+
+	return errors.Wrapf(client.Update(ctx, configMap), "error updating ConfigMap %q", configMap.Name)
+}
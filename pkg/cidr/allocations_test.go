@@ -0,0 +1,165 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cidr_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	namespace = "test-ns"
+	pool      = v1alpha1.CIDRAllocationPoolGlobalnet
+)
+
+var _ = Describe("CIDRAllocations", func() {
+	var (
+		client    controllerClient.Client
+		configMap *corev1.ConfigMap
+		east      cidr.ClusterInfo
+		west      cidr.ClusterInfo
+	)
+
+	BeforeEach(func() {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: namespace,
+			},
+		}
+
+		east = cidr.ClusterInfo{
+			ClusterID: "east",
+			CIDRs:     []string{"169.254.0.0/19"},
+		}
+
+		west = cidr.ClusterInfo{
+			ClusterID: "west",
+			CIDRs:     []string{"169.254.32.0/19"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap).Build()
+	})
+
+	getAllocations := func(ctx context.Context) map[string]*cidr.ClusterInfo {
+		Expect(client.Get(ctx, controllerClient.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+
+		allocations, err := cidr.GetAllocations(ctx, client, configMap, pool)
+		Expect(err).To(Succeed())
+
+		return allocations
+	}
+
+	It("should set, retrieve and remove a cluster's allocation", func(ctx SpecContext) {
+		Expect(cidr.SetAllocation(ctx, client, namespace, pool, east)).To(Succeed())
+		Expect(cidr.SetAllocation(ctx, client, namespace, pool, west)).To(Succeed())
+		Expect(cidr.SetAllocation(ctx, client, namespace, v1alpha1.CIDRAllocationPoolClustersetIP, cidr.ClusterInfo{
+			ClusterID: "north",
+			CIDRs:     []string{"243.0.0.0/20"},
+		})).To(Succeed())
+
+		Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"east": &east, "west": &west}))
+
+		east.CIDRs = []string{"169.254.64.0/19"}
+		Expect(cidr.SetAllocation(ctx, client, namespace, pool, east)).To(Succeed())
+		Expect(getAllocations(ctx)).To(HaveKeyWithValue("east", &east))
+
+		removed, err := cidr.RemoveAllocation(ctx, client, namespace, pool, east.ClusterID)
+		Expect(err).To(Succeed())
+		Expect(removed).To(BeTrue())
+		Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"west": &west}))
+
+		removed, err = cidr.RemoveAllocation(ctx, client, namespace, pool, east.ClusterID)
+		Expect(err).To(Succeed())
+		Expect(removed).To(BeFalse())
+	})
+
+	Context("on CommitAllocation", func() {
+		It("should record the allocation and increment the allocation generation", func(ctx SpecContext) {
+			Expect(client.Get(ctx, controllerClient.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			Expect(cidr.CommitAllocation(ctx, client, configMap, pool, east)).To(Succeed())
+
+			Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"east": &east}))
+			Expect(configMap.Annotations).To(HaveKeyWithValue(cidr.AllocationGenerationAnnotation, "1"))
+		})
+
+		When("another allocation was committed concurrently", func() {
+			It("should roll back the allocation and return a conflict error", func(ctx SpecContext) {
+				Expect(client.Get(ctx, controllerClient.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+				staleConfigMap := configMap.DeepCopy()
+
+				Expect(cidr.CommitAllocation(ctx, client, configMap, pool, east)).To(Succeed())
+
+				err := cidr.CommitAllocation(ctx, client, staleConfigMap, pool, west)
+				Expect(apierrors.IsConflict(err)).To(BeTrue())
+				Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"east": &east}))
+
+				updatedEast := cidr.ClusterInfo{
+					ClusterID: east.ClusterID,
+					CIDRs:     []string{"169.254.64.0/19"},
+				}
+
+				err = cidr.CommitAllocation(ctx, client, staleConfigMap, pool, updatedEast)
+				Expect(apierrors.IsConflict(err)).To(BeTrue())
+				Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"east": &east}))
+			})
+		})
+	})
+
+	When("allocations are recorded in the ConfigMap", func() {
+		BeforeEach(func() {
+			Expect(cidr.AddClusterInfoData(configMap, east)).To(Succeed())
+			Expect(cidr.AddClusterInfoData(configMap, west)).To(Succeed())
+		})
+
+		It("should include them", func(ctx SpecContext) {
+			Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"east": &east, "west": &west}))
+		})
+
+		It("should migrate them to CIDRAllocations", func(ctx SpecContext) {
+			existing := cidr.ClusterInfo{
+				ClusterID: "west",
+				CIDRs:     []string{"169.254.96.0/19"},
+			}
+
+			Expect(cidr.SetAllocation(ctx, client, namespace, pool, existing)).To(Succeed())
+
+			Expect(cidr.MigrateAllocations(ctx, client, configMap, pool)).To(Succeed())
+			Expect(configMap.Data).ToNot(HaveKey(cidr.ClusterInfoKey))
+
+			Expect(getAllocations(ctx)).To(Equal(map[string]*cidr.ClusterInfo{"east": &east, "west": &existing}))
+
+			allocations := &v1alpha1.CIDRAllocationList{}
+			Expect(client.List(ctx, allocations, controllerClient.InNamespace(namespace))).To(Succeed())
+			Expect(allocations.Items).To(HaveLen(2))
+		})
+	})
+})
@@ -19,11 +19,12 @@ limitations under the License.
 package cidr
 
 import (
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"math/bits"
+	"math/big"
 	"net"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -37,15 +38,17 @@ type ClusterInfo struct {
 }
 
 type Info struct {
-	CIDR           string
-	AllocationSize uint
+	CIDR string
+	// The number of addresses in each allocation, a power of 2. It's a big integer since IPv6 allocations are commonly
+	// /64 or larger.
+	AllocationSize *big.Int
 	Clusters       map[string]*ClusterInfo
 }
 
-type allocationInfo struct {
-	network *net.IPNet
-	size    int
-	lastIP  uint32
+// addressRange is an inclusive range of addresses in a single address family.
+type addressRange struct {
+	first *big.Int
+	last  *big.Int
 }
 
 func unmarshalClusterInfo(fromConfigMap *corev1.ConfigMap) ([]ClusterInfo, error) {
@@ -149,91 +152,197 @@ func CheckForOverlappingCIDRs(infoMap map[string]*ClusterInfo, cidr, clusterID s
 	return nil
 }
 
+// Overlap describes a CIDR of one cluster which overlaps a CIDR of another.
+type Overlap struct {
+	ClusterID      string
+	CIDR           string
+	OtherClusterID string
+	OtherCIDR      string
+}
+
+// FindOverlaps returns the overlapping CIDRs of the given clusters, each overlap being reported once with the clusters
+// in ID order.
+func FindOverlaps(infoMap map[string]*ClusterInfo) ([]Overlap, error) {
+	clusters := make([]*ClusterInfo, 0, len(infoMap))
+	for _, ci := range infoMap {
+		clusters = append(clusters, ci)
+	}
+
+	slices.SortFunc(clusters, func(a, b *ClusterInfo) int {
+		return strings.Compare(a.ClusterID, b.ClusterID)
+	})
+
+	var overlaps []Overlap
+
+	for i, ci := range clusters {
+		for _, other := range clusters[i+1:] {
+			for _, cidr := range ci.CIDRs {
+				for _, otherCIDR := range other.CIDRs {
+					overlap, err := isOverlappingCIDR([]string{otherCIDR}, cidr)
+					if err != nil {
+						return nil, errors.Wrap(err, "unable to validate overlapping CIDRs")
+					}
+
+					if overlap {
+						overlaps = append(overlaps, Overlap{
+							ClusterID:      ci.ClusterID,
+							CIDR:           cidr,
+							OtherClusterID: other.ClusterID,
+							OtherCIDR:      otherCIDR,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return overlaps, nil
+}
+
 func Allocate(info *Info) (string, error) {
 	_, network, err := net.ParseCIDR(info.CIDR)
 	if err != nil {
 		return "", fmt.Errorf("unable to parse CIDR %q", info.CIDR)
 	}
 
-	var allocated []allocationInfo
+	allocated, err := parseAllocations(info)
+	if err != nil {
+		return "", err
+	}
+
+	return allocateBySize(info.AllocationSize, network, allocated)
+}
+
+func parseAllocations(info *Info) ([]*net.IPNet, error) {
+	var allocated []*net.IPNet
 
 	for _, cluster := range info.Clusters {
 		for _, cidr := range cluster.CIDRs {
 			_, n, err := net.ParseCIDR(cidr)
 			if err != nil {
-				return "", fmt.Errorf("unable to parse CIDR %q", cidr)
+				return nil, fmt.Errorf("unable to parse CIDR %q", cidr)
 			}
 
-			allocated = append(allocated, newAllocationInfo(n))
+			allocated = append(allocated, n)
 		}
 	}
 
-	return allocateBySize(info.AllocationSize, network, allocated)
+	return allocated, nil
 }
 
-func allocateBySize(size uint, network *net.IPNet, allocated []allocationInfo) (string, error) {
-	bitSize := bits.LeadingZeros(0) - bits.LeadingZeros(size-1)
-	_, totalbits := network.Mask.Size()
-	clusterPrefix := totalbits - bitSize
-	mask := net.CIDRMask(clusterPrefix, totalbits)
+// allocateBySize allocates a block of the given size, using best-fit placement over the free ranges in the network so
+// that holes left by released allocations get filled before larger ranges are split up. This is O(n log n) in the
+// number of allocations.
+func allocateBySize(size *big.Int, network *net.IPNet, allocated []*net.IPNet) (string, error) {
+	if size == nil || size.Sign() <= 0 {
+		return "", errors.New("the allocation size must be > 0")
+	}
+
+	bitSize := prefixBits(size)
+	ones, totalbits := network.Mask.Size()
+
+	if bitSize > totalbits-ones {
+		return "", fmt.Errorf("no more allocations available in %q", network)
+	}
 
-	cidr := fmt.Sprintf("%s/%d", network.IP, clusterPrefix)
+	blockSize := new(big.Int).Lsh(big.NewInt(1), uint(bitSize)) //nolint:gosec // The size can't be negative
 
-	for {
-		last, err := allocateByCIDR(cidr, network, allocated)
-		if err == nil {
-			break
+	first := bestFit(freeRanges(network, allocated), blockSize)
+	if first == nil {
+		return "", fmt.Errorf("no more allocations available in %q", network)
+	}
+
+	allocation := net.IPNet{
+		IP:   intToIP(first, len(network.IP)),
+		Mask: net.CIDRMask(totalbits-bitSize, totalbits),
+	}
+
+	return allocation.String(), nil
+}
+
+// freeRanges returns the ranges of the network which aren't covered by any of the allocated networks, sorted by
+// address. Allocations from the other address family are ignored.
+func freeRanges(network *net.IPNet, allocated []*net.IPNet) []addressRange {
+	pool := newAddressRange(network)
+
+	used := make([]addressRange, 0, len(allocated))
+
+	for _, n := range allocated {
+		if len(n.IP) != len(network.IP) {
+			continue
 		}
 
-		if last == 0 {
-			return "", err
+		r := newAddressRange(n)
+		if r.last.Cmp(pool.first) >= 0 && r.first.Cmp(pool.last) <= 0 {
+			used = append(used, r)
 		}
+	}
+
+	slices.SortFunc(used, func(a, b addressRange) int {
+		return a.first.Cmp(b.first)
+	})
 
-		nextNet := net.IPNet{
-			IP:   uintToIP(last + 1),
-			Mask: mask,
+	var free []addressRange
+
+	next := pool.first
+
+	for _, r := range used {
+		if r.first.Cmp(next) > 0 {
+			free = append(free, addressRange{first: next, last: new(big.Int).Sub(r.first, big.NewInt(1))})
 		}
 
-		cidr = nextNet.String()
+		if r.last.Cmp(next) >= 0 {
+			next = new(big.Int).Add(r.last, big.NewInt(1))
+		}
 	}
 
-	return cidr, nil
+	if next.Cmp(pool.last) <= 0 {
+		free = append(free, addressRange{first: next, last: pool.last})
+	}
+
+	return free
 }
 
-func allocateByCIDR(cidr string, network *net.IPNet, allocatedCIDRs []allocationInfo) (uint32, error) {
-	requestedIP, requestedNetwork, _ := net.ParseCIDR(cidr)
-	if !network.Contains(requestedIP) {
-		return 0, fmt.Errorf("no more allocations available in %q", network)
-	}
+// bestFit returns the first address of the first aligned block of the given size in the smallest free range that can
+// hold one, or nil if none can. Ties go to the lowest range so that a pool which has never had allocations released is
+// filled in address order.
+func bestFit(free []addressRange, size *big.Int) *big.Int {
+	var best, bestSize *big.Int
 
-	newAllocation := newAllocationInfo(requestedNetwork)
-	if !network.Contains(uintToIP(newAllocation.lastIP)) {
-		return 0, fmt.Errorf("%s not a valid subnet of %v", uintToIP(newAllocation.lastIP), network)
-	}
+	mask := new(big.Int).Sub(size, big.NewInt(1))
 
-	for _, allocated := range allocatedCIDRs {
-		if allocated.network.Contains(requestedIP) {
-			// subset of already allocated, try next
-			return allocated.lastIP, fmt.Errorf("%s is a subset of already allocated CIDR %v", cidr, allocated.network)
+	for _, r := range free {
+		// Round the start of the range up to the next multiple of the size.
+		first := new(big.Int).Add(r.first, mask)
+		first.AndNot(first, mask)
+
+		last := new(big.Int).Add(first, mask)
+		if last.Cmp(r.last) > 0 {
+			continue
 		}
 
-		if requestedNetwork.Contains(allocated.network.IP) {
-			// already allocated is subset of requested, no valid lastIP
-			return newAllocation.lastIP, fmt.Errorf("%s overlaps with already allocated globalCidr %s", cidr, allocated.network)
+		rangeSize := new(big.Int).Sub(r.last, r.first)
+		if best == nil || rangeSize.Cmp(bestSize) < 0 {
+			best = first
+			bestSize = rangeSize
 		}
 	}
 
-	return 0, nil
+	return best
 }
 
-func newAllocationInfo(network *net.IPNet) allocationInfo {
+func newAddressRange(network *net.IPNet) addressRange {
 	ones, total := network.Mask.Size()
-	size := total - ones
 
-	return allocationInfo{
-		network: network,
-		size:    size,
-		lastIP:  ipToUint(network.IP) + 1<<size - 1,
+	first := ipToInt(network.IP)
+
+	last := new(big.Int).Lsh(big.NewInt(1), uint(total-ones)) //nolint:gosec // The size can't be negative
+	last.Sub(last, big.NewInt(1))
+	last.Or(last, first)
+
+	return addressRange{
+		first: first,
+		last:  last,
 	}
 }
 
@@ -257,55 +366,102 @@ func isOverlappingCIDR(cidrList []string, cidr string) (bool, error) {
 	return false, nil
 }
 
-func ipToUint(ip net.IP) uint32 {
-	intIP := ip
-	if len(ip) == 16 {
-		intIP = ip[12:16]
-	}
-
-	return binary.BigEndian.Uint32(intIP)
+// ipToInt converts the given network IP, as parsed by net.ParseCIDR, to an integer. IPv4 network IPs are 4 bytes long,
+// so IPv4-mapped IPv6 networks are kept apart from IPv4 ones.
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip)
 }
 
-func uintToIP(ip uint32) net.IP {
-	netIP := make(net.IP, 4)
-	binary.BigEndian.PutUint32(netIP, ip)
+// intToIP converts the given integer to an IP address of the given length in bytes, i.e. 4 for IPv4 and 16 for IPv6.
+func intToIP(ip *big.Int, length int) net.IP {
+	netIP := make(net.IP, length)
+	ip.FillBytes(netIP)
 
 	return netIP
 }
 
-func GetValidAllocationSize(cidrRange string, allocationSize uint) (uint, error) {
+// GetValidAllocationSize returns the given allocation size rounded up to a power of 2, if allocations of that size can be
+// made from the given range.
+func GetValidAllocationSize(cidrRange string, allocationSize *big.Int) (*big.Int, error) {
+	_, network, err := net.ParseCIDR(cidrRange)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // No need to wrap here
+	}
+
+	if allocationSize == nil || allocationSize.Sign() <= 0 {
+		return nil, errors.New("cluster size must be > 0")
+	}
+
+	ones, totalbits := network.Mask.Size()
+
+	// Allocations may use at most half of the range.
+	maxBits := totalbits - ones - 1
+	if maxBits < 0 || prefixBits(allocationSize) > maxBits {
+		limit := new(big.Int)
+		if maxBits >= 0 {
+			limit.Lsh(big.NewInt(1), uint(maxBits))
+		}
+
+		return nil, fmt.Errorf("cluster size %s, should be <= %s", allocationSize, limit)
+	}
+
+	return nextPowerOf2(allocationSize), nil
+}
+
+// ValidateAllocation checks that the given CIDR could have been allocated from the given range: it must be within the
+// range, and its size must be a valid allocation size for the range.
+func ValidateAllocation(cidrRange, allocated string) error {
 	_, network, err := net.ParseCIDR(cidrRange)
 	if err != nil {
-		return 0, err //nolint:wrapcheck // No need to wrap here
+		return err //nolint:wrapcheck // No need to wrap here
+	}
+
+	_, allocatedNetwork, err := net.ParseCIDR(allocated)
+	if err != nil {
+		return err //nolint:wrapcheck // No need to wrap here
 	}
 
 	ones, totalbits := network.Mask.Size()
-	availableSize := uint(1) << uint(totalbits-ones) //nolint:gosec // Ignore overflow conversion int -> uint
-	userClusterSize := allocationSize
-	allocationSize = nextPowerOf2(allocationSize)
+	allocatedOnes, allocatedTotalbits := allocatedNetwork.Mask.Size()
 
-	if allocationSize > (availableSize / 2) {
-		return 0, fmt.Errorf("cluster size %d, should be <= %d", userClusterSize, availableSize/2)
+	if allocatedTotalbits != totalbits || allocatedOnes < ones || !network.Contains(allocatedNetwork.IP) {
+		return fmt.Errorf("%s isn't within %s", allocated, cidrRange)
 	}
 
-	if allocationSize == 0 {
-		return 0, errors.New("cluster size must be > 0")
+	_, err = GetValidAllocationSize(cidrRange, networkSize(allocatedNetwork))
+
+	return err
+}
+
+// Size returns the number of addresses in the given CIDR.
+func Size(cidr string) (*big.Int, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // No need to wrap here
 	}
 
-	return allocationSize, nil
+	return networkSize(network), nil
+}
+
+func networkSize(network *net.IPNet) *big.Int {
+	ones, bits := network.Mask.Size()
+
+	return new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)) //nolint:gosec // The size can't be negative
+}
+
+// AllocationSize returns the given number of addresses as an allocation size.
+func AllocationSize(size uint) *big.Int {
+	return new(big.Int).SetUint64(uint64(size))
+}
+
+// prefixBits returns the number of host bits needed for an allocation of the given size, which must be > 0.
+func prefixBits(size *big.Int) int {
+	return new(big.Int).Sub(size, big.NewInt(1)).BitLen()
 }
 
-// Refer: https://graphics.stanford.edu/~seander/bithacks.html#RoundUpPowerOf2
-func nextPowerOf2(n uint) uint {
-	n--
-	n |= n >> 1
-	n |= n >> 2
-	n |= n >> 4
-	n |= n >> 8
-	n |= n >> 16
-	n++
-
-	return n
+// nextPowerOf2 returns the smallest power of 2 which is >= n, which must be > 0.
+func nextPowerOf2(n *big.Int) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(prefixBits(n))) //nolint:gosec // The size can't be negative
 }
 
 func IsCIDRPreConfigured(clusterID string, clustersetIPNetworks map[string]*ClusterInfo) bool {
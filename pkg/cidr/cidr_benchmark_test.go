@@ -0,0 +1,72 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cidr_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+)
+
+func BenchmarkAllocate(b *testing.B) {
+	for _, clusters := range []int{1000, 4000} {
+		b.Run(fmt.Sprintf("%d clusters", clusters), func(b *testing.B) {
+			benchmarkAllocate(b, newBenchmarkInfo(clusters, 1))
+		})
+
+		b.Run(fmt.Sprintf("%d clusters with released allocations", clusters), func(b *testing.B) {
+			benchmarkAllocate(b, newBenchmarkInfo(clusters, 3))
+		})
+	}
+}
+
+func benchmarkAllocate(b *testing.B, info *cidr.Info) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		if _, err := cidr.Allocate(info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// newBenchmarkInfo returns a /8 pool, with /20 allocations, in which every step-th allocation of the given number of
+// clusters has been released.
+func newBenchmarkInfo(clusters, step int) *cidr.Info {
+	info := &cidr.Info{
+		CIDR:           "242.0.0.0/8",
+		AllocationSize: cidr.AllocationSize(4096),
+		Clusters:       map[string]*cidr.ClusterInfo{},
+	}
+
+	for i := range clusters {
+		if step > 1 && i%step == 0 {
+			continue
+		}
+
+		clusterID := fmt.Sprintf("cluster%d", i)
+		info.Clusters[clusterID] = &cidr.ClusterInfo{
+			ClusterID: clusterID,
+			CIDRs:     []string{fmt.Sprintf("242.%d.%d.0/20", i/16, (i%16)*16)},
+		}
+	}
+
+	return info
+}
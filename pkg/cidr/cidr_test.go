@@ -19,6 +19,9 @@ limitations under the License.
 package cidr_test
 
 import (
+	"fmt"
+	"math/big"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/submariner-operator/pkg/cidr"
@@ -32,7 +35,7 @@ var _ = Describe("Allocate", func() {
 	BeforeEach(func() {
 		cidrInfo = cidr.Info{
 			CIDR:           "169.254.0.0/16",
-			AllocationSize: 8192,
+			AllocationSize: cidr.AllocationSize(8192),
 			Clusters:       map[string]*cidr.ClusterInfo{},
 		}
 	})
@@ -69,6 +72,25 @@ var _ = Describe("Allocate", func() {
 		})
 	})
 
+	When("many CIDRs are allocated in sequence", func() {
+		It("should allocate them in address order", func() {
+			cidrInfo.CIDR = "242.0.0.0/8"
+			cidrInfo.AllocationSize = cidr.AllocationSize(4096)
+
+			for i := range 1000 {
+				result, err := cidr.Allocate(&cidrInfo)
+				Expect(err).To(Succeed())
+				Expect(result).To(Equal(fmt.Sprintf("242.%d.%d.0/20", i/16, (i%16)*16)))
+
+				clusterID := fmt.Sprintf("cluster%d", i)
+				cidrInfo.Clusters[clusterID] = &cidr.ClusterInfo{
+					ClusterID: clusterID,
+					CIDRs:     []string{result},
+				}
+			}
+		})
+	})
+
 	When("there is an unallocated block available at beginning", func() {
 		It("should allocate the CIDR block at the beginning", func() {
 			cidrInfo.Clusters["cluster1"] = &cidr.ClusterInfo{
@@ -102,7 +124,7 @@ var _ = Describe("Allocate", func() {
 
 	When("all CIDRs are already allocated", func() {
 		It("should return an error", func() {
-			cidrInfo.AllocationSize = 32768
+			cidrInfo.AllocationSize = cidr.AllocationSize(32768)
 
 			cidrInfo.Clusters["cluster1"] = &cidr.ClusterInfo{
 				ClusterID: "cluster1",
@@ -121,7 +143,7 @@ var _ = Describe("Allocate", func() {
 
 	When("there's not enough space for new allocation", func() {
 		It("should return an error", func() {
-			cidrInfo.AllocationSize = 32768
+			cidrInfo.AllocationSize = cidr.AllocationSize(32768)
 
 			cidrInfo.Clusters["cluster1"] = &cidr.ClusterInfo{
 				ClusterID: "cluster1",
@@ -139,6 +161,137 @@ var _ = Describe("Allocate", func() {
 	})
 })
 
+var _ = DescribeTable("Allocate in either address family",
+	func(cidrRange string, allocationSize *big.Int, allocated []string, expected string) {
+		info := &cidr.Info{
+			CIDR:           cidrRange,
+			AllocationSize: allocationSize,
+			Clusters: map[string]*cidr.ClusterInfo{
+				"east": {ClusterID: "east", CIDRs: allocated},
+			},
+		}
+
+		result, err := cidr.Allocate(info)
+
+		if expected == "" {
+			Expect(err).To(HaveOccurred())
+		} else {
+			Expect(err).To(Succeed())
+			Expect(result).To(Equal(expected))
+		}
+	},
+	Entry("IPv4 first block", "242.0.0.0/8", cidr.AllocationSize(65536), nil, "242.0.0.0/16"),
+	Entry("IPv4 after an allocated block", "242.0.0.0/8", cidr.AllocationSize(65536), []string{"242.0.0.0/16"}, "242.1.0.0/16"),
+	Entry("IPv4 in a gap", "242.0.0.0/8", cidr.AllocationSize(65536), []string{"242.0.0.0/16", "242.2.0.0/16"}, "242.1.0.0/16"),
+	Entry("IPv4 at the end of the address space", "255.255.255.0/24", cidr.AllocationSize(128), []string{"255.255.255.0/25"},
+		"255.255.255.128/25"),
+	Entry("IPv4 when full", "255.255.255.0/24", cidr.AllocationSize(128), []string{"255.255.255.0/25", "255.255.255.128/25"}, ""),
+	Entry("IPv6 first block", "fd00:1234::/96", cidr.AllocationSize(65536), nil, "fd00:1234::/112"),
+	Entry("IPv6 after an allocated block", "fd00:1234::/96", cidr.AllocationSize(65536), []string{"fd00:1234::/112"}, "fd00:1234::1:0/112"),
+	Entry("IPv6 in a gap", "fd00:1234::/96", cidr.AllocationSize(65536), []string{"fd00:1234::/112", "fd00:1234::2:0/112"},
+		"fd00:1234::1:0/112"),
+	Entry("IPv6 largest block size", "fd00:1234::/48", pow2(63), []string{"fd00:1234::/65"}, "fd00:1234:0:0:8000::/65"),
+	Entry("IPv6 /64 block", "fd00:1234::/48", pow2(64), []string{"fd00:1234::/64"}, "fd00:1234:0:1::/64"),
+	Entry("IPv6 block larger than /64", "fd00:1234::/48", pow2(72), []string{"fd00:1234::/56"}, "fd00:1234:0:100::/56"),
+	Entry("IPv6 past a larger block", "fd00:1234::/48", cidr.AllocationSize(256), []string{"fd00:1234::/120", "fd00:1234::100/120"},
+		"fd00:1234::200/120"),
+	Entry("IPv6 at the end of the address space", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120", cidr.AllocationSize(128),
+		[]string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/121"}, "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff80/121"),
+	Entry("IPv6 when full", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120", cidr.AllocationSize(128),
+		[]string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/121", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff80/121"}, ""),
+	Entry("IPv6 ignoring IPv4 allocations", "fd00:1234::/96", cidr.AllocationSize(65536), []string{"242.0.0.0/16"}, "fd00:1234::/112"),
+	Entry("IPv4 in the smallest gap that fits", "242.0.0.0/8", cidr.AllocationSize(65536),
+		[]string{"242.2.0.0/16", "242.4.0.0/16", "242.6.0.0/16"}, "242.3.0.0/16"),
+	Entry("IPv4 past a gap that's too small", "242.0.0.0/8", cidr.AllocationSize(65536),
+		[]string{"242.0.0.0/16", "242.1.128.0/17", "242.3.0.0/16"}, "242.2.0.0/16"),
+	Entry("IPv4 aligned within an unaligned gap", "242.0.0.0/8", cidr.AllocationSize(65536), []string{"242.0.0.0/17"}, "242.1.0.0/16"),
+	Entry("IPv4 past an allocation overlapping the range", "242.0.0.0/16", cidr.AllocationSize(256), []string{"242.0.0.0/15"}, ""),
+	Entry("IPv6 in the smallest gap that fits", "fd00:1234::/96", cidr.AllocationSize(65536),
+		[]string{"fd00:1234::2:0/112", "fd00:1234::4:0/112", "fd00:1234::6:0/112"}, "fd00:1234::3:0/112"),
+)
+
+var _ = DescribeTable("GetValidAllocationSize",
+	func(cidrRange string, allocationSize, expected *big.Int) {
+		result, err := cidr.GetValidAllocationSize(cidrRange, allocationSize)
+
+		if expected == nil {
+			Expect(err).To(HaveOccurred())
+		} else {
+			Expect(err).To(Succeed())
+			Expect(result.String()).To(Equal(expected.String()))
+		}
+	},
+	Entry("IPv4 power of 2", "242.0.0.0/8", cidr.AllocationSize(65536), cidr.AllocationSize(65536)),
+	Entry("IPv4 rounded up", "242.0.0.0/8", cidr.AllocationSize(1000), cidr.AllocationSize(1024)),
+	Entry("IPv4 half of the range", "242.0.0.0/16", cidr.AllocationSize(32768), cidr.AllocationSize(32768)),
+	Entry("IPv4 more than half of the range", "242.0.0.0/16", cidr.AllocationSize(32769), nil),
+	Entry("IPv4 zero", "242.0.0.0/8", cidr.AllocationSize(0), nil),
+	Entry("no size", "242.0.0.0/8", nil, nil),
+	Entry("IPv6 power of 2", "fd00::/96", cidr.AllocationSize(65536), cidr.AllocationSize(65536)),
+	Entry("IPv6 rounded up", "fd00::/48", cidr.AllocationSize(3000), cidr.AllocationSize(4096)),
+	Entry("IPv6 /64", "fd00::/48", pow2(64), pow2(64)),
+	Entry("IPv6 rounded up beyond 64 bits", "fd00::/8", new(big.Int).Add(pow2(63), big.NewInt(1)), pow2(64)),
+	Entry("IPv6 half of the range", "fd00::/48", pow2(79), pow2(79)),
+	Entry("IPv6 more than half of the range", "fd00::/120", cidr.AllocationSize(256), nil),
+	Entry("invalid range", "fd00::/129", cidr.AllocationSize(256), nil),
+)
+
+var _ = DescribeTable("ValidateAllocation",
+	func(cidrRange, allocated string, valid bool) {
+		err := cidr.ValidateAllocation(cidrRange, allocated)
+
+		if valid {
+			Expect(err).To(Succeed())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+	Entry("IPv4 within the range", "242.0.0.0/8", "242.1.0.0/16", true),
+	Entry("IPv4 half of the range", "242.0.0.0/16", "242.0.128.0/17", true),
+	Entry("IPv4 more than half of the range", "242.0.0.0/16", "242.0.0.0/16", false),
+	Entry("IPv4 outside the range", "242.0.0.0/8", "243.0.0.0/16", false),
+	Entry("IPv4 larger than the range", "242.0.0.0/16", "242.0.0.0/8", false),
+	Entry("IPv6 within the range", "fd00::/96", "fd00::1:0/112", true),
+	Entry("IPv6 /64 within a /48", "fd00:1234::/48", "fd00:1234:0:5::/64", true),
+	Entry("different families", "242.0.0.0/8", "fd00::/112", false),
+	Entry("invalid allocation", "242.0.0.0/8", "242.0.0.0/33", false),
+)
+
+var _ = DescribeTable("Size",
+	func(network, expected string) {
+		size, err := cidr.Size(network)
+		Expect(err).To(Succeed())
+		Expect(size.String()).To(Equal(expected))
+	},
+	Entry("IPv4", "242.0.0.0/24", "256"),
+	Entry("IPv4 single address", "242.0.0.1/32", "1"),
+	Entry("IPv6 /64", "fd00:1234::/64", "18446744073709551616"),
+)
+
+var _ = Describe("Allocating /64 blocks from a /48", func() {
+	It("should allocate them in address order until the range is full", func() {
+		info := &cidr.Info{
+			CIDR:           "fd00:1234::/48",
+			AllocationSize: pow2(64),
+			Clusters:       map[string]*cidr.ClusterInfo{},
+		}
+
+		for i, expected := range []string{"fd00:1234::/64", "fd00:1234:0:1::/64", "fd00:1234:0:2::/64"} {
+			result, err := cidr.Allocate(info)
+			Expect(err).To(Succeed())
+			Expect(result).To(Equal(expected))
+
+			clusterID := fmt.Sprintf("cluster%d", i)
+			info.Clusters[clusterID] = &cidr.ClusterInfo{ClusterID: clusterID, CIDRs: []string{result}}
+		}
+
+		info.Clusters["big"] = &cidr.ClusterInfo{ClusterID: "big", CIDRs: []string{"fd00:1234::/49", "fd00:1234:0:8000::/49"}}
+
+		_, err := cidr.Allocate(info)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("CheckForOverlappingCIDRs", func() {
 	var (
 		existingCIDRs []string
@@ -260,6 +413,47 @@ var _ = Describe("IsValid", func() {
 	})
 })
 
+var _ = DescribeTable("IsValid in either address family",
+	func(value string, valid bool) {
+		if valid {
+			Expect(cidr.IsValid(value)).To(Succeed())
+		} else {
+			Expect(cidr.IsValid(value)).ToNot(Succeed())
+		}
+	},
+	Entry("IPv4 global", "242.0.0.0/8", true),
+	Entry("IPv4 unspecified", "0.0.0.0/8", false),
+	Entry("IPv6 unique local", "fd00:1234::/48", true),
+	Entry("IPv6 global unicast", "2001:db8::/32", true),
+	Entry("IPv6 unspecified", "::/64", false),
+	Entry("IPv6 loopback", "::1/128", false),
+	Entry("IPv6 link-local", "fe80::/64", false),
+	Entry("IPv6 link-local multicast", "ff02::/16", false),
+	Entry("IPv6 invalid prefix", "fd00::/129", false),
+)
+
+var _ = DescribeTable("CheckForOverlappingCIDRs in either address family",
+	func(existing []string, requested string, overlapping bool) {
+		err := cidr.CheckForOverlappingCIDRs(map[string]*cidr.ClusterInfo{
+			"east": {ClusterID: "east", CIDRs: existing},
+		}, requested, "west")
+
+		if overlapping {
+			Expect(err).To(HaveOccurred())
+		} else {
+			Expect(err).To(Succeed())
+		}
+	},
+	Entry("IPv4 disjoint", []string{"10.10.10.0/24"}, "10.10.20.0/24", false),
+	Entry("IPv4 superset", []string{"10.10.10.0/24"}, "10.10.0.0/16", true),
+	Entry("IPv4 subset", []string{"10.10.0.0/16"}, "10.10.10.0/24", true),
+	Entry("IPv6 disjoint", []string{"fd00:1::/64"}, "fd00:2::/64", false),
+	Entry("IPv6 superset", []string{"fd00:1::/64"}, "fd00::/16", true),
+	Entry("IPv6 subset", []string{"fd00::/16"}, "fd00:1::/64", true),
+	Entry("IPv6 partial overlap", []string{"fd00:1::/64"}, "fd00:1::8000:0:0:0/65", true),
+	Entry("different families", []string{"10.0.0.0/8"}, "fd00::/8", false),
+)
+
 var _ = Describe("AddClusterInfoData", func() {
 	It("should succeed", func() {
 		configMap := &corev1.ConfigMap{
@@ -318,3 +512,40 @@ var _ = Describe("AddClusterInfoData", func() {
 		}))
 	})
 })
+
+var _ = Describe("FindOverlaps", func() {
+	It("should report each overlap once in cluster ID order", func() {
+		overlaps, err := cidr.FindOverlaps(map[string]*cidr.ClusterInfo{
+			"west":  {ClusterID: "west", CIDRs: []string{"10.10.0.0/16", "100.96.0.0/16"}},
+			"east":  {ClusterID: "east", CIDRs: []string{"10.10.10.0/24", "100.95.0.0/16"}},
+			"north": {ClusterID: "north", CIDRs: []string{"10.20.0.0/16", "fd00::/64"}},
+			"south": {ClusterID: "south", CIDRs: []string{"10.30.0.0/16", "fd00::/48"}},
+		})
+		Expect(err).To(Succeed())
+		Expect(overlaps).To(Equal([]cidr.Overlap{
+			{ClusterID: "east", CIDR: "10.10.10.0/24", OtherClusterID: "west", OtherCIDR: "10.10.0.0/16"},
+			{ClusterID: "north", CIDR: "fd00::/64", OtherClusterID: "south", OtherCIDR: "fd00::/48"},
+		}))
+	})
+
+	It("should return no overlaps for disjoint clusters", func() {
+		overlaps, err := cidr.FindOverlaps(map[string]*cidr.ClusterInfo{
+			"east": {ClusterID: "east", CIDRs: []string{"10.10.0.0/16"}},
+			"west": {ClusterID: "west", CIDRs: []string{"10.20.0.0/16"}},
+		})
+		Expect(err).To(Succeed())
+		Expect(overlaps).To(BeEmpty())
+	})
+
+	It("should return an error for an invalid CIDR", func() {
+		_, err := cidr.FindOverlaps(map[string]*cidr.ClusterInfo{
+			"east": {ClusterID: "east", CIDRs: []string{"10.10.0.0/16"}},
+			"west": {ClusterID: "west", CIDRs: []string{"10.20.0.0/33"}},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func pow2(n uint) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), n)
+}
@@ -0,0 +1,160 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cidr
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// Utilization describes how much of a CIDR pool is allocated. The counts are big integers since IPv6 pools can hold
+// more than 2^64 addresses.
+type Utilization struct {
+	// The number of addresses in the pool.
+	TotalAddresses *big.Int
+	// The number of addresses covered by allocations.
+	UsedAddresses *big.Int
+	// The number of addresses which aren't allocated.
+	FreeAddresses *big.Int
+	// The number of further allocations of the requested size which fit in the pool.
+	AvailableAllocations *big.Int
+	// The fraction of the free addresses, from 0 to 1, which can't be used by allocations of the requested size.
+	Fragmentation float64
+	// The CIDRs the next allocations of the requested size would receive, in order. This has fewer entries than
+	// requested if the pool runs out.
+	NextAllocations []string
+}
+
+// GetUtilization reports the utilization of the given pool for allocations of the given size, nil meaning the pool's
+// allocation size, and previews the next count allocations. Nothing is allocated.
+func GetUtilization(info *Info, size *big.Int, count int) (*Utilization, error) {
+	_, network, err := net.ParseCIDR(info.CIDR)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse CIDR %q", info.CIDR)
+	}
+
+	if size == nil {
+		size = info.AllocationSize
+	}
+
+	allocated, err := parseAllocations(info)
+	if err != nil {
+		return nil, err
+	}
+
+	free := freeRanges(network, allocated)
+	pool := newAddressRange(network)
+
+	utilization := &Utilization{
+		TotalAddresses:       pool.size(),
+		FreeAddresses:        new(big.Int),
+		AvailableAllocations: new(big.Int),
+	}
+
+	for _, r := range free {
+		utilization.FreeAddresses.Add(utilization.FreeAddresses, r.size())
+	}
+
+	utilization.UsedAddresses = new(big.Int).Sub(utilization.TotalAddresses, utilization.FreeAddresses)
+
+	ones, totalbits := network.Mask.Size()
+
+	if size == nil || size.Sign() <= 0 || prefixBits(size) > totalbits-ones {
+		if utilization.FreeAddresses.Sign() > 0 {
+			utilization.Fragmentation = 1
+		}
+
+		return utilization, nil
+	}
+
+	bitSize := prefixBits(size)
+	blockSize := new(big.Int).Lsh(big.NewInt(1), uint(bitSize)) //nolint:gosec // The size can't be negative
+
+	for _, r := range free {
+		utilization.AvailableAllocations.Add(utilization.AvailableAllocations, r.blocks(blockSize))
+	}
+
+	if utilization.FreeAddresses.Sign() > 0 {
+		usable := new(big.Int).Mul(utilization.AvailableAllocations, blockSize)
+		ratio, _ := new(big.Rat).SetFrac(usable, utilization.FreeAddresses).Float64()
+		utilization.Fragmentation = 1 - ratio
+	}
+
+	mask := net.CIDRMask(totalbits-bitSize, totalbits)
+
+	for range count {
+		first := bestFit(free, blockSize)
+		if first == nil {
+			break
+		}
+
+		free = takeBlock(free, first, blockSize)
+
+		allocation := net.IPNet{IP: intToIP(first, len(network.IP)), Mask: mask}
+		utilization.NextAllocations = append(utilization.NextAllocations, allocation.String())
+	}
+
+	return utilization, nil
+}
+
+func (r addressRange) size() *big.Int {
+	size := new(big.Int).Sub(r.last, r.first)
+	return size.Add(size, big.NewInt(1))
+}
+
+// blocks returns the number of aligned blocks of the given size which fit in the range.
+func (r addressRange) blocks(size *big.Int) *big.Int {
+	mask := new(big.Int).Sub(size, big.NewInt(1))
+
+	first := new(big.Int).Add(r.first, mask)
+	first.AndNot(first, mask)
+
+	end := new(big.Int).Add(r.last, big.NewInt(1))
+	if end.Cmp(first) <= 0 {
+		return new(big.Int)
+	}
+
+	return end.Sub(end, first).Div(end, size)
+}
+
+// takeBlock removes the block of the given size starting at first from the free range containing it.
+func takeBlock(free []addressRange, first, size *big.Int) []addressRange {
+	last := new(big.Int).Add(first, size)
+	last.Sub(last, big.NewInt(1))
+
+	for i, r := range free {
+		if r.first.Cmp(first) > 0 || r.last.Cmp(last) < 0 {
+			continue
+		}
+
+		var remaining []addressRange
+
+		if r.first.Cmp(first) < 0 {
+			remaining = append(remaining, addressRange{first: r.first, last: new(big.Int).Sub(first, big.NewInt(1))})
+		}
+
+		if r.last.Cmp(last) > 0 {
+			remaining = append(remaining, addressRange{first: new(big.Int).Add(last, big.NewInt(1)), last: r.last})
+		}
+
+		return append(free[:i], append(remaining, free[i+1:]...)...)
+	}
+
+	return free
+}
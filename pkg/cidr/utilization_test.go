@@ -0,0 +1,139 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cidr_test
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/cidr"
+)
+
+var _ = Describe("GetUtilization", func() {
+	var (
+		info  *cidr.Info
+		size  *big.Int
+		count int
+	)
+
+	BeforeEach(func() {
+		info = &cidr.Info{
+			CIDR:           "169.254.0.0/16",
+			AllocationSize: cidr.AllocationSize(8192),
+			Clusters:       map[string]*cidr.ClusterInfo{},
+		}
+
+		size = nil
+		count = 3
+	})
+
+	allocate := func(clusterID string, cidrs ...string) {
+		info.Clusters[clusterID] = &cidr.ClusterInfo{ClusterID: clusterID, CIDRs: cidrs}
+	}
+
+	verify := func(total, used, free, available string, fragmentation float64, next ...string) {
+		initial := len(info.Clusters)
+
+		utilization, err := cidr.GetUtilization(info, size, count)
+		Expect(err).To(Succeed())
+		Expect(utilization.TotalAddresses.String()).To(Equal(total))
+		Expect(utilization.UsedAddresses.String()).To(Equal(used))
+		Expect(utilization.FreeAddresses.String()).To(Equal(free))
+		Expect(utilization.AvailableAllocations.String()).To(Equal(available))
+		Expect(utilization.Fragmentation).To(BeNumerically("~", fragmentation, 0.0001))
+		Expect(utilization.NextAllocations).To(Equal(next))
+		Expect(info.Clusters).To(HaveLen(initial))
+	}
+
+	When("nothing is allocated", func() {
+		It("should report the whole pool as free", func() {
+			verify("65536", "0", "65536", "8", 0, "169.254.0.0/19", "169.254.32.0/19", "169.254.64.0/19")
+		})
+	})
+
+	When("the pool has holes", func() {
+		BeforeEach(func() {
+			allocate("east", "169.254.32.0/19")
+			allocate("west", "169.254.64.0/24")
+		})
+
+		It("should report the fragmentation and preview best-fit allocations", func() {
+			verify("65536", "8448", "57088", "6", 1-float64(6*8192)/57088,
+				"169.254.0.0/19", "169.254.96.0/19", "169.254.128.0/19")
+		})
+	})
+
+	When("a different allocation size is requested", func() {
+		BeforeEach(func() {
+			size = cidr.AllocationSize(32768)
+		})
+
+		It("should report the utilization for that size", func() {
+			verify("65536", "0", "65536", "2", 0, "169.254.0.0/17", "169.254.128.0/17")
+		})
+	})
+
+	When("the pool is full", func() {
+		BeforeEach(func() {
+			size = cidr.AllocationSize(32768)
+			allocate("east", "169.254.0.0/17")
+			allocate("west", "169.254.128.0/17")
+		})
+
+		It("should report no available allocations", func() {
+			verify("65536", "65536", "0", "0", 0)
+		})
+	})
+
+	When("the remaining space is too fragmented", func() {
+		BeforeEach(func() {
+			size = cidr.AllocationSize(32768)
+			allocate("east", "169.254.64.0/18")
+			allocate("west", "169.254.128.0/18")
+		})
+
+		It("should report it all as fragmented", func() {
+			verify("65536", "32768", "32768", "0", 1)
+		})
+	})
+
+	When("the pool is IPv6", func() {
+		BeforeEach(func() {
+			info.CIDR = "fd00:1234::/48"
+			info.AllocationSize = new(big.Int).Lsh(big.NewInt(1), 48)
+			count = 1
+
+			allocate("east", "fd00:1234::/80")
+		})
+
+		It("should report counts beyond 64 bits", func() {
+			verify("1208925819614629174706176", "281474976710656", "1208925819333154197995520", "4294967295", 0,
+				"fd00:1234:0:0:1::/80")
+		})
+	})
+
+	When("the pool CIDR is invalid", func() {
+		It("should return an error", func() {
+			info.CIDR = "169.254.0.0/33"
+
+			_, err := cidr.GetUtilization(info, size, count)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
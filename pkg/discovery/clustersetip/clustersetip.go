@@ -25,6 +25,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/reporter"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
 	"github.com/submariner-io/submariner-operator/pkg/cidr"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -54,8 +55,9 @@ func ValidateClustersetIPConfiguration(clustersetIPInfo *Info, netconfig Config,
 	clustersetIPClusterSize := netconfig.AllocationSize
 	clustersetIPCIDR := netconfig.ClustersetIPCIDR
 
-	if clustersetIPClusterSize != 0 && clustersetIPClusterSize != clustersetIPInfo.AllocationSize {
-		clusterSize, err := cidr.GetValidAllocationSize(clustersetIPInfo.CIDR, clustersetIPClusterSize)
+	if clustersetIPClusterSize != 0 && (clustersetIPInfo.AllocationSize == nil ||
+		cidr.AllocationSize(clustersetIPClusterSize).Cmp(clustersetIPInfo.AllocationSize) != 0) {
+		clusterSize, err := cidr.GetValidAllocationSize(clustersetIPInfo.CIDR, cidr.AllocationSize(clustersetIPClusterSize))
 		if err != nil {
 			return "", status.Error(err, "invalid cluster size")
 		}
@@ -96,7 +98,7 @@ func GetClustersetIPNetworks(ctx context.Context, client controllerClient.Client
 		return nil, nil, errors.Wrap(err, "error reading ClustersetIPCidrRange")
 	}
 
-	clustersetIPInfo.Clusters, err = cidr.ExtractClusterInfo(configMap)
+	clustersetIPInfo.Clusters, err = cidr.GetAllocations(ctx, client, configMap, v1alpha1.CIDRAllocationPoolClustersetIP)
 
 	return &clustersetIPInfo, configMap, err //nolint:wrapcheck // No need to wrap
 }
@@ -175,7 +177,7 @@ func AllocateCIDRFromConfigMap(ctx context.Context, brokerAdminClient controller
 	enabled := false
 	userClustersetIPCIDR := config.ClustersetIPCIDR
 
-	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	retryErr := retry.RetryOnConflict(cidr.AllocationBackoff, func() error {
 		status.Start("Retrieving ClustersetIP information from the Broker")
 		defer status.End()
 
@@ -205,13 +207,13 @@ func AllocateCIDRFromConfigMap(ctx context.Context, brokerAdminClient controller
 
 			status.Start("Updating the ClustersetIP information on the Broker")
 
-			err = updateConfigMap(ctx, brokerAdminClient, clustersetIPConfigMap, newClusterInfo)
+			err = cidr.CommitAllocation(ctx, brokerAdminClient, clustersetIPConfigMap, v1alpha1.CIDRAllocationPoolClustersetIP, newClusterInfo)
 			if apierrors.IsConflict(err) {
-				status.Warning("Conflict occurred updating the ClustersetIP ConfigMap - retrying")
+				status.Warning("Conflict occurred updating the ClustersetIP allocation - retrying")
 				// Conflict with allocation, retry with user given CIDR to try reallocation
 				config.ClustersetIPCIDR = userClustersetIPCIDR
 			} else {
-				return status.Error(err, "error updating the ClustersetIP ConfigMap")
+				return status.Error(err, "error updating the ClustersetIP allocation")
 			}
 
 			return err
@@ -19,6 +19,8 @@ limitations under the License.
 package clustersetip_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/admiral/pkg/reporter"
@@ -107,6 +109,55 @@ var _ = Describe("AllocateCIDRFromConfigMap", func() {
 	})
 })
 
+var _ = Describe("Release", func() {
+	var client controllerClient.Client
+
+	BeforeEach(func(ctx SpecContext) {
+		client = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		Expect(clustersetip.CreateConfigMap(ctx, client, true, "168.254.0.0/16",
+			8192, namespace)).To(Succeed())
+	})
+
+	allocate := func(ctx context.Context, clusterID string) string {
+		netconfig := &clustersetip.Config{ClusterID: clusterID}
+		Expect(clustersetip.AllocateCIDRFromConfigMap(ctx, client, namespace, netconfig, reporter.Klog())).To(BeTrue())
+
+		return netconfig.ClustersetIPCIDR
+	}
+
+	It("should release the cluster's clustersetip CIDR for reallocation", func(ctx SpecContext) {
+		eastCIDR := allocate(ctx, "east")
+		allocate(ctx, "west")
+
+		Expect(clustersetip.Release(ctx, client, namespace, "east")).To(Succeed())
+
+		clustersetipInfo, _, err := clustersetip.GetClustersetIPNetworks(ctx, client, namespace)
+		Expect(err).To(Succeed())
+		Expect(clustersetipInfo.Clusters).ToNot(HaveKey("east"))
+		Expect(clustersetipInfo.Clusters).To(HaveKey("west"))
+
+		Expect(allocate(ctx, "north")).To(Equal(eastCIDR))
+	})
+
+	When("the cluster's clustersetip CIDR is recorded in the ConfigMap", func() {
+		It("should remove it from the ConfigMap", func(ctx SpecContext) {
+			configMap, err := clustersetip.GetConfigMap(ctx, client, namespace)
+			Expect(err).To(Succeed())
+			Expect(cidr.AddClusterInfoData(configMap, cidr.ClusterInfo{
+				ClusterID: "east",
+				CIDRs:     []string{"168.254.0.0/20"},
+			})).To(Succeed())
+			Expect(client.Update(ctx, configMap)).To(Succeed())
+
+			Expect(clustersetip.Release(ctx, client, namespace, "east")).To(Succeed())
+
+			clustersetipInfo, _, err := clustersetip.GetClustersetIPNetworks(ctx, client, namespace)
+			Expect(err).To(Succeed())
+			Expect(clustersetipInfo.Clusters).To(BeEmpty())
+		})
+	})
+})
+
 var _ = Describe("ValidateExistingClustersetIPNetworks", func() {
 	var client controllerClient.Client
 
@@ -25,11 +25,13 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
 	"github.com/submariner-io/submariner-operator/pkg/cidr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -84,18 +86,6 @@ func NewClustersetIPConfigMap(clustersetIPEnabled bool, defaultClusteretIPCidrRa
 	return cm, nil
 }
 
-func updateConfigMap(ctx context.Context, client controllerClient.Client, configMap *corev1.ConfigMap, newCluster cidr.ClusterInfo,
-) error {
-	err := cidr.AddClusterInfoData(configMap, newCluster)
-	if err != nil {
-		return errors.Wrapf(err, "error adding ClusterInfo")
-	}
-
-	err = client.Update(ctx, configMap)
-
-	return errors.Wrapf(err, "error updating clustersetip ConfigMap")
-}
-
 //nolint:wrapcheck // No need to wrap here
 func GetConfigMap(ctx context.Context, client controllerClient.Client, namespace string) (*corev1.ConfigMap, error) {
 	cm := &corev1.ConfigMap{}
@@ -109,3 +99,36 @@ func DeleteConfigMap(ctx context.Context, client controllerClient.Client, namesp
 		Namespace: namespace,
 	}})
 }
+
+// MigrateAllocations moves the clustersetip CIDR allocations recorded in the clustersetip ConfigMap, if any, to
+// CIDRAllocations.
+func MigrateAllocations(ctx context.Context, client controllerClient.Client, namespace string) error {
+	configMap, err := GetConfigMap(ctx, client, namespace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error retrieving clustersetip ConfigMap")
+	}
+
+	return cidr.MigrateAllocations(ctx, client, configMap, v1alpha1.CIDRAllocationPoolClustersetIP) //nolint:wrapcheck // No need to wrap
+}
+
+// Release releases the clustersetip CIDRs allocated to a cluster so that they can be allocated to other clusters.
+func Release(ctx context.Context, client controllerClient.Client, namespace, clusterID string) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := GetConfigMap(ctx, client, namespace)
+		if apierrors.IsNotFound(err) {
+			configMap = nil
+		} else if err != nil {
+			return errors.Wrap(err, "error retrieving clustersetip ConfigMap")
+		}
+
+		_, err = cidr.ReleaseAllocation(ctx, client, namespace, configMap, v1alpha1.CIDRAllocationPoolClustersetIP, clusterID)
+
+		return err
+	})
+
+	return retryErr //nolint:wrapcheck // No need to wrap here
+}
@@ -22,9 +22,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/reporter"
+	"github.com/submariner-io/submariner-operator/api/v1alpha1"
 	"github.com/submariner-io/submariner-operator/pkg/cidr"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -41,6 +45,10 @@ type Config struct {
 	ClusterID   string
 	GlobalCIDR  string
 	ClusterSize uint
+	// The number of global CIDR blocks the cluster needs; 0 is treated as 1. Blocks beyond the first are allocated with
+	// the same size and returned in AdditionalGlobalCIDRs. A cluster's existing blocks are never released.
+	Blocks                uint
+	AdditionalGlobalCIDRs []string
 }
 
 func AllocateGlobalCIDR(globalnetInfo *Info) (string, error) {
@@ -64,8 +72,9 @@ func ValidateGlobalnetConfiguration(globalnetInfo *Info, netconfig Config, statu
 	globalnetClusterSize := netconfig.ClusterSize
 	globalnetCIDR := netconfig.GlobalCIDR
 
-	if globalnetInfo.Enabled && globalnetClusterSize != 0 && globalnetClusterSize != globalnetInfo.AllocationSize {
-		clusterSize, err := cidr.GetValidAllocationSize(globalnetInfo.CIDR, globalnetClusterSize)
+	if globalnetInfo.Enabled && globalnetClusterSize != 0 && (globalnetInfo.AllocationSize == nil ||
+		cidr.AllocationSize(globalnetClusterSize).Cmp(globalnetInfo.AllocationSize) != 0) {
+		clusterSize, err := cidr.GetValidAllocationSize(globalnetInfo.CIDR, cidr.AllocationSize(globalnetClusterSize))
 		if err != nil {
 			return "", status.Error(err, "invalid cluster size")
 		}
@@ -94,7 +103,7 @@ func ValidateGlobalnetConfiguration(globalnetInfo *Info, netconfig Config, statu
 		} else if globalnetClusterSize != 0 {
 			status.Warning("Globalnet is not enabled on the Broker - ignoring the specified cluster size")
 
-			globalnetInfo.AllocationSize = 0
+			globalnetInfo.AllocationSize = nil
 		}
 	}
 
@@ -126,7 +135,7 @@ func GetGlobalNetworks(ctx context.Context, client controllerClient.Client, brok
 		}
 	}
 
-	globalnetInfo.Clusters, err = cidr.ExtractClusterInfo(configMap)
+	globalnetInfo.Clusters, err = cidr.GetAllocations(ctx, client, configMap, v1alpha1.CIDRAllocationPoolGlobalnet)
 
 	return &globalnetInfo, configMap, err //nolint:wrapcheck // No need to wrap
 }
@@ -175,6 +184,47 @@ func AssignGlobalnetIPs(globalnetInfo *Info, netconfig Config, status reporter.I
 	return globalnetCIDR, nil
 }
 
+// AllocateAdditionalGlobalCIDRs returns all the global CIDR blocks of the cluster, starting with the assigned
+// netconfig.GlobalCIDR: the blocks it already has, followed by newly allocated ones until there are as many as
+// netconfig.Blocks.
+func AllocateAdditionalGlobalCIDRs(globalnetInfo *Info, netconfig *Config, status reporter.Interface) ([]string, error) {
+	globalCIDRs := []string{netconfig.GlobalCIDR}
+
+	if existing := globalnetInfo.Clusters[netconfig.ClusterID]; existing != nil && len(existing.CIDRs) > 0 &&
+		existing.CIDRs[0] == netconfig.GlobalCIDR {
+		globalCIDRs = slices.Clone(existing.CIDRs)
+	}
+
+	if uint(len(globalCIDRs)) >= netconfig.Blocks {
+		return globalCIDRs, nil
+	}
+
+	status.Start("Allocating additional global CIDR blocks")
+	defer status.End()
+
+	// Allocate from a copy of the pool in which the cluster holds the blocks allocated so far.
+	info := globalnetInfo.Info
+	info.Clusters = maps.Clone(globalnetInfo.Clusters)
+
+	for uint(len(globalCIDRs)) < netconfig.Blocks {
+		info.Clusters[netconfig.ClusterID] = &cidr.ClusterInfo{
+			ClusterID: netconfig.ClusterID,
+			CIDRs:     globalCIDRs,
+		}
+
+		globalCIDR, err := cidr.Allocate(&info)
+		if err != nil {
+			return nil, status.Error(err, "unable to allocate an additional global CIDR")
+		}
+
+		globalCIDRs = append(globalCIDRs, globalCIDR)
+	}
+
+	status.Success("Allocated additional global CIDRs %s", strings.Join(globalCIDRs[1:], ", "))
+
+	return globalCIDRs, nil
+}
+
 func ValidateExistingGlobalNetworks(ctx context.Context, client controllerClient.Client, namespace string) error {
 	globalnetInfo, _, err := GetGlobalNetworks(ctx, client, namespace)
 	if err != nil {
@@ -197,7 +247,9 @@ func ValidateExistingGlobalNetworks(ctx context.Context, client controllerClient
 func AllocateAndUpdateGlobalCIDRConfigMap(ctx context.Context, brokerAdminClient controllerClient.Client, brokerNamespace string,
 	netconfig *Config, status reporter.Interface,
 ) error {
-	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	userGlobalCIDR := netconfig.GlobalCIDR
+
+	retryErr := retry.RetryOnConflict(cidr.AllocationBackoff, func() error {
 		status.Start("Retrieving Globalnet information from the Broker")
 		defer status.End()
 
@@ -217,20 +269,31 @@ func AllocateAndUpdateGlobalCIDRConfigMap(ctx context.Context, brokerAdminClient
 				return status.Error(err, "error assigning Globalnet IPs")
 			}
 
+			var globalCIDRs []string
+
+			globalCIDRs, err = AllocateAdditionalGlobalCIDRs(globalnetInfo, netconfig, status)
+			if err != nil {
+				return err
+			}
+
+			netconfig.AdditionalGlobalCIDRs = globalCIDRs[1:]
+
 			if globalnetInfo.Clusters[netconfig.ClusterID] == nil ||
-				globalnetInfo.Clusters[netconfig.ClusterID].CIDRs[0] != netconfig.GlobalCIDR {
+				!slices.Equal(globalnetInfo.Clusters[netconfig.ClusterID].CIDRs, globalCIDRs) {
 				newClusterInfo := cidr.ClusterInfo{
 					ClusterID: netconfig.ClusterID,
-					CIDRs:     []string{netconfig.GlobalCIDR},
+					CIDRs:     globalCIDRs,
 				}
 
 				status.Start("Updating the Globalnet information on the Broker")
 
-				err = updateConfigMap(ctx, brokerAdminClient, globalnetConfigMap, newClusterInfo)
+				err = cidr.CommitAllocation(ctx, brokerAdminClient, globalnetConfigMap, v1alpha1.CIDRAllocationPoolGlobalnet, newClusterInfo)
 				if apierrors.IsConflict(err) {
-					status.Warning("Conflict occurred updating the Globalnet ConfigMap - retrying")
+					status.Warning("Conflict occurred updating the Globalnet allocation - retrying")
+					// Conflict with allocation, retry with user given CIDR to try reallocation
+					netconfig.GlobalCIDR = userGlobalCIDR
 				} else {
-					return status.Error(err, "error updating the Globalnet ConfigMap")
+					return status.Error(err, "error updating the Globalnet allocation")
 				}
 
 				return err
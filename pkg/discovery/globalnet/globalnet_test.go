@@ -19,6 +19,11 @@ limitations under the License.
 package globalnet_test
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/admiral/pkg/reporter"
@@ -27,6 +32,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 const namespace = "test-ns"
@@ -105,6 +111,159 @@ var _ = Describe("AllocateAndUpdateGlobalCIDRConfigMap", func() {
 			Expect(netconfig.GlobalCIDR).To(Equal("168.254.0.0/20"))
 		})
 	})
+
+	When("additional global CIDR blocks are requested", func() {
+		It("should allocate them along with the existing ones", func(ctx SpecContext) {
+			netconfig := &globalnet.Config{
+				ClusterID: "east",
+			}
+
+			Expect(globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, client, namespace,
+				netconfig, reporter.Klog())).To(Succeed())
+			Expect(netconfig.GlobalCIDR).To(Equal("168.254.0.0/19"))
+			Expect(netconfig.AdditionalGlobalCIDRs).To(BeEmpty())
+
+			Expect(globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, client, namespace,
+				&globalnet.Config{ClusterID: "west"}, reporter.Klog())).To(Succeed())
+
+			netconfig = &globalnet.Config{
+				ClusterID: "east",
+				Blocks:    3,
+			}
+
+			Expect(globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, client, namespace,
+				netconfig, reporter.Klog())).To(Succeed())
+			Expect(netconfig.GlobalCIDR).To(Equal("168.254.0.0/19"))
+			Expect(netconfig.AdditionalGlobalCIDRs).To(Equal([]string{"168.254.64.0/19", "168.254.96.0/19"}))
+
+			globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, client, namespace)
+			Expect(err).To(Succeed())
+			Expect(globalnetInfo.Clusters).To(HaveKeyWithValue("east", &cidr.ClusterInfo{
+				ClusterID: "east",
+				CIDRs:     []string{"168.254.0.0/19", "168.254.64.0/19", "168.254.96.0/19"},
+			}))
+
+			By("Requesting fewer blocks")
+
+			netconfig.GlobalCIDR = ""
+			netconfig.Blocks = 1
+
+			Expect(globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, client, namespace,
+				netconfig, reporter.Klog())).To(Succeed())
+			Expect(netconfig.GlobalCIDR).To(Equal("168.254.0.0/19"))
+			Expect(netconfig.AdditionalGlobalCIDRs).To(Equal([]string{"168.254.64.0/19", "168.254.96.0/19"}))
+		})
+	})
+})
+
+var _ = Describe("Concurrent AllocateAndUpdateGlobalCIDRConfigMap", func() {
+	const numClusters = 40
+
+	It("should not allocate overlapping CIDRs", func(ctx SpecContext) {
+		// Widen the window between reading the allocations and committing a new one so that allocations interleave.
+		client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, client controllerClient.WithWatch, list controllerClient.ObjectList,
+				opts ...controllerClient.ListOption,
+			) error {
+				err := client.List(ctx, list, opts...)
+				time.Sleep(time.Millisecond)
+
+				return err
+			},
+		}).Build()
+		Expect(globalnet.CreateConfigMap(ctx, client, true, globalnet.DefaultGlobalnetCIDR,
+			globalnet.DefaultGlobalnetClusterSize, namespace)).To(Succeed())
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, numClusters)
+
+		for i := range numClusters {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				errs <- globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, client, namespace,
+					&globalnet.Config{ClusterID: fmt.Sprintf("cluster-%d", i)}, reporter.Silent())
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			Expect(err).To(Succeed())
+		}
+
+		globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, client, namespace)
+		Expect(err).To(Succeed())
+		Expect(globalnetInfo.Clusters).To(HaveLen(numClusters))
+
+		for clusterID, info := range globalnetInfo.Clusters {
+			Expect(info.CIDRs).To(HaveLen(1))
+			Expect(cidr.CheckForOverlappingCIDRs(globalnetInfo.Clusters, info.CIDRs[0], clusterID)).To(Succeed())
+		}
+	})
+})
+
+var _ = Describe("Release", func() {
+	var client controllerClient.Client
+
+	BeforeEach(func(ctx SpecContext) {
+		client = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		Expect(globalnet.CreateConfigMap(ctx, client, true, "168.254.0.0/16",
+			8192, namespace)).To(Succeed())
+	})
+
+	allocate := func(ctx context.Context, clusterID string) string {
+		netconfig := &globalnet.Config{ClusterID: clusterID}
+		Expect(globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, client, namespace, netconfig, reporter.Klog())).To(Succeed())
+
+		return netconfig.GlobalCIDR
+	}
+
+	It("should release the cluster's global CIDR for reallocation", func(ctx SpecContext) {
+		eastCIDR := allocate(ctx, "east")
+		allocate(ctx, "west")
+
+		Expect(globalnet.Release(ctx, client, namespace, "east")).To(Succeed())
+
+		globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, client, namespace)
+		Expect(err).To(Succeed())
+		Expect(globalnetInfo.Clusters).ToNot(HaveKey("east"))
+		Expect(globalnetInfo.Clusters).To(HaveKey("west"))
+
+		Expect(allocate(ctx, "north")).To(Equal(eastCIDR))
+
+		Expect(globalnet.Release(ctx, client, namespace, "east")).To(Succeed())
+	})
+
+	When("the cluster's global CIDR is recorded in the ConfigMap", func() {
+		It("should remove it from the ConfigMap", func(ctx SpecContext) {
+			configMap, err := globalnet.GetConfigMap(ctx, client, namespace)
+			Expect(err).To(Succeed())
+			Expect(cidr.AddClusterInfoData(configMap, cidr.ClusterInfo{
+				ClusterID: "east",
+				CIDRs:     []string{"168.254.0.0/19"},
+			})).To(Succeed())
+			Expect(client.Update(ctx, configMap)).To(Succeed())
+
+			Expect(globalnet.Release(ctx, client, namespace, "east")).To(Succeed())
+
+			globalnetInfo, _, err := globalnet.GetGlobalNetworks(ctx, client, namespace)
+			Expect(err).To(Succeed())
+			Expect(globalnetInfo.Clusters).To(BeEmpty())
+		})
+	})
+
+	When("the globalnet ConfigMap does not exist", func() {
+		It("should succeed", func(ctx SpecContext) {
+			Expect(globalnet.DeleteConfigMap(ctx, client, namespace)).To(Succeed())
+			Expect(globalnet.Release(ctx, client, namespace, "east")).To(Succeed())
+		})
+	})
 })
 
 var _ = Describe("ValidateExistingGlobalNetworks", func() {
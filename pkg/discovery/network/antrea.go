@@ -0,0 +1,159 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Antrea plugin settings, named after the corresponding antrea-agent.conf keys.
+const (
+	AntreaTrafficEncapModeSetting = "trafficEncapMode"
+	AntreaTunnelTypeSetting       = "tunnelType"
+)
+
+const (
+	antreaConfigMapName       = "antrea-config"
+	antreaAgentConfigKey      = "antrea-agent.conf"
+	antreaControllerConfigKey = "antrea-controller.conf"
+	antreaDefaultEncapMode    = "encap"
+	antreaDefaultTunnelType   = "geneve"
+)
+
+type antreaAgentConfig struct {
+	TrafficEncapMode string `json:"trafficEncapMode"`
+	TunnelType       string `json:"tunnelType"`
+	ServiceCIDR      string `json:"serviceCIDR"`
+	ServiceCIDRv6    string `json:"serviceCIDRv6"`
+}
+
+type antreaControllerConfig struct {
+	NodeIPAM struct {
+		EnableNodeIPAM bool     `json:"enableNodeIPAM"`
+		ClusterCIDRs   []string `json:"clusterCIDRs"`
+		ServiceCIDR    string   `json:"serviceCIDR"`
+		ServiceCIDRv6  string   `json:"serviceCIDRv6"`
+	} `json:"nodeIPAM"`
+}
+
+//nolint:nilnil // Intentional as the purpose is to discover.
+func discoverAntreaNetwork(ctx context.Context, client controllerClient.Client) (*ClusterNetwork, error) {
+	antreaConfig, err := findAntreaConfigMap(ctx, client)
+	if err != nil || antreaConfig == nil {
+		return nil, err
+	}
+
+	agentConfig := antreaAgentConfig{}
+	if err := yaml.Unmarshal([]byte(antreaConfig.Data[antreaAgentConfigKey]), &agentConfig); err != nil {
+		return nil, errors.Wrapf(err, "error parsing %s in the Antrea ConfigMap %q", antreaAgentConfigKey, antreaConfig.Name)
+	}
+
+	controllerConfig := antreaControllerConfig{}
+	if err := yaml.Unmarshal([]byte(antreaConfig.Data[antreaControllerConfigKey]), &controllerConfig); err != nil {
+		return nil, errors.Wrapf(err, "error parsing %s in the Antrea ConfigMap %q", antreaControllerConfigKey, antreaConfig.Name)
+	}
+
+	clusterNetwork := &ClusterNetwork{
+		NetworkPlugin:         Antrea,
+		NetworkPluginEvidence: evidence("ConfigMap", antreaConfig),
+		PluginSettings: map[string]string{
+			AntreaTrafficEncapModeSetting: valueOrDefault(agentConfig.TrafficEncapMode, antreaDefaultEncapMode),
+			AntreaTunnelTypeSetting:       valueOrDefault(agentConfig.TunnelType, antreaDefaultTunnelType),
+		},
+	}
+
+	// Pod CIDRs are only configured when Antrea allocates them itself; otherwise they're allocated by Kubernetes and
+	// the generic discovery is used.
+	if controllerConfig.NodeIPAM.EnableNodeIPAM {
+		clusterNetwork.PodCIDRs = controllerConfig.NodeIPAM.ClusterCIDRs
+		clusterNetwork.PodCIDRsSource = SourcePluginConfig
+		clusterNetwork.PodCIDRsConfidence = ConfidenceHigh
+	}
+
+	clusterNetwork.ServiceCIDRs = nonEmpty(agentConfig.ServiceCIDR, agentConfig.ServiceCIDRv6)
+	if len(clusterNetwork.ServiceCIDRs) == 0 {
+		clusterNetwork.ServiceCIDRs = nonEmpty(controllerConfig.NodeIPAM.ServiceCIDR, controllerConfig.NodeIPAM.ServiceCIDRv6)
+	}
+
+	if len(clusterNetwork.ServiceCIDRs) > 0 {
+		clusterNetwork.ServiceCIDRsSource = SourcePluginConfig
+	}
+
+	return clusterNetwork, nil
+}
+
+// findAntreaConfigMap returns Antrea's ConfigMap; older releases add a hash suffix to its name, so the ConfigMaps are
+// only listed if it isn't found by name.
+//
+//nolint:nilnil // Intentional as the purpose is to find.
+func findAntreaConfigMap(ctx context.Context, client controllerClient.Client) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+
+	err := client.Get(ctx, controllerClient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: antreaConfigMapName}, configMap)
+	if err == nil {
+		return configMap, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "error retrieving the Antrea ConfigMap %q", antreaConfigMapName)
+	}
+
+	cmList := &corev1.ConfigMapList{}
+
+	err = client.List(ctx, cmList, controllerClient.InNamespace(metav1.NamespaceSystem))
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing ConfigMaps for Antrea discovery")
+	}
+
+	for i := range cmList.Items {
+		if strings.HasPrefix(cmList.Items[i].Name, antreaConfigMapName+"-") {
+			return &cmList.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	"github.com/submariner-io/submariner/pkg/cni"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testAntreaPodCIDR     = "10.10.0.0/16"
+	testAntreaServiceCIDR = "10.96.0.0/12"
+)
+
+var _ = Describe("Antrea Network", func() {
+	When("the antrea ConfigMap specifies the service CIDR and node IPAM", func() {
+		It("should return a ClusterNetwork with the plugin name, CIDRs and settings set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newAntreaConfigMap("antrea-config", `
+trafficEncapMode: noEncap
+serviceCIDR: `+testAntreaServiceCIDR, `
+nodeIPAM:
+  enableNodeIPAM: true
+  clusterCIDRs: [`+testAntreaPodCIDR+`]
+`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.Antrea))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testAntreaPodCIDR}))
+			Expect(clusterNet.PodCIDRsConfidence).To(Equal(network.ConfidenceHigh))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testAntreaServiceCIDR}))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.AntreaTrafficEncapModeSetting, "noEncap"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.AntreaTunnelTypeSetting, "geneve"))
+		})
+	})
+
+	When("the antrea ConfigMap has a hashed name and doesn't specify the CIDRs", func() {
+		It("should return a ClusterNetwork with the generic CIDRs", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newAntreaConfigMap("antrea-config-b4gk7t8mt4", "tunnelType: vxlan", ""),
+				fakeKubeControllerManagerPod())
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.Antrea))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testPodCIDR}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.AntreaTrafficEncapModeSetting, "encap"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.AntreaTunnelTypeSetting, "vxlan"))
+		})
+	})
+
+	When("the antrea ConfigMap can't be parsed", func() {
+		It("should return a ClusterNetwork with the generic plugin", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newAntreaConfigMap("antrea-config", "trafficEncapMode: [", ""))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Generic))
+		})
+	})
+
+	When("the antrea ConfigMap does not exist", func() {
+		It("should return a ClusterNetwork with the generic plugin", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx)
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Generic))
+		})
+	})
+})
+
+func newAntreaConfigMap(name, agentConfig, controllerConfig string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string]string{
+			"antrea-agent.conf":      agentConfig,
+			"antrea-controller.conf": controllerConfig,
+		},
+	}
+}
@@ -22,29 +22,69 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/submariner/pkg/cni"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Calico plugin settings, named after the corresponding IPPool fields. They give the encapsulation used by the IPPools,
+// Always, CrossSubnet or Never.
+const (
+	CalicoIPIPModeSetting  = "ipipMode"
+	CalicoVXLANModeSetting = "vxlanMode"
+)
+
+const calicoEncapsulationNever = "Never"
+
+// IPPools are served by the Calico CRDs, or by the Calico API server when it is installed.
+var calicoIPPoolGVKs = []schema.GroupVersionKind{
+	{Group: "crd.projectcalico.org", Version: "v1", Kind: "IPPoolList"},
+	{Group: "projectcalico.org", Version: "v3", Kind: "IPPoolList"},
+}
+
+type calicoIPPool struct {
+	cidr      string
+	ipipMode  string
+	vxlanMode string
+}
+
 //nolint:nilnil // Intentional as the purpose is to discover.
 func discoverCalicoNetwork(ctx context.Context, client controllerClient.Client) (*ClusterNetwork, error) {
+	calicoEvidence := "ConfigMap calico-config"
+
 	found, err := calicoConfigMapExists(ctx, client)
 	if err != nil {
 		return nil, err
 	}
 
 	if !found {
+		calicoEvidence = "DaemonSet calico-node"
+
 		found, err = calicoDaemonSetExists(ctx, client)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// Listing IPPools may be forbidden, notably on clusters which don't run Calico; they're then ignored.
+	ipPools, err := findCalicoIPPools(ctx, client)
+	if err != nil {
+		log.Error(err, "Unable to list the Calico IPPools")
+
+		ipPools = nil
+	}
+
 	if !found {
-		return nil, nil
+		if len(ipPools) == 0 {
+			return nil, nil
+		}
+
+		calicoEvidence = "IPPools"
 	}
 
 	clusterNetwork, err := discoverNetwork(ctx, client)
@@ -52,9 +92,86 @@ func discoverCalicoNetwork(ctx context.Context, client controllerClient.Client)
 		return nil, err
 	}
 
-	if clusterNetwork != nil {
-		clusterNetwork.NetworkPlugin = cni.Calico
-		return clusterNetwork, nil
+	if len(ipPools) == 0 {
+		if clusterNetwork != nil {
+			clusterNetwork.NetworkPlugin = cni.Calico
+			clusterNetwork.NetworkPluginEvidence = calicoEvidence
+
+			return clusterNetwork, nil
+		}
+
+		return nil, nil
+	}
+
+	if clusterNetwork == nil {
+		clusterNetwork = &ClusterNetwork{}
+	}
+
+	clusterNetwork.NetworkPlugin = cni.Calico
+	clusterNetwork.NetworkPluginEvidence = calicoEvidence
+	clusterNetwork.PodCIDRs = nil
+	clusterNetwork.PodCIDRsSource = SourceCalicoIPPool
+	clusterNetwork.PodCIDRsConfidence = ConfidenceHigh
+	clusterNetwork.PluginSettings = map[string]string{
+		CalicoIPIPModeSetting:  calicoEncapsulationNever,
+		CalicoVXLANModeSetting: calicoEncapsulationNever,
+	}
+
+	for _, ipPool := range ipPools {
+		clusterNetwork.PodCIDRs = append(clusterNetwork.PodCIDRs, ipPool.cidr)
+
+		// Pools may use different encapsulations; the first one which uses any is reported.
+		if ipPool.ipipMode != "" && ipPool.ipipMode != calicoEncapsulationNever &&
+			clusterNetwork.PluginSettings[CalicoIPIPModeSetting] == calicoEncapsulationNever {
+			clusterNetwork.PluginSettings[CalicoIPIPModeSetting] = ipPool.ipipMode
+		}
+
+		if ipPool.vxlanMode != "" && ipPool.vxlanMode != calicoEncapsulationNever &&
+			clusterNetwork.PluginSettings[CalicoVXLANModeSetting] == calicoEncapsulationNever {
+			clusterNetwork.PluginSettings[CalicoVXLANModeSetting] = ipPool.vxlanMode
+		}
+	}
+
+	return clusterNetwork, nil
+}
+
+// findCalicoIPPools returns the enabled IPPools, from the first API group which serves any.
+func findCalicoIPPools(ctx context.Context, client controllerClient.Client) ([]calicoIPPool, error) {
+	for _, gvk := range calicoIPPoolGVKs {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+
+		err := client.List(ctx, list)
+		if resource.IsNotFoundErr(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing Calico IPPools in %s", gvk.GroupVersion())
+		}
+
+		var ipPools []calicoIPPool
+
+		for i := range list.Items {
+			spec, _, _ := unstructured.NestedMap(list.Items[i].Object, "spec")
+
+			disabled, _, _ := unstructured.NestedBool(spec, "disabled")
+			cidr, _, _ := unstructured.NestedString(spec, "cidr")
+
+			if disabled || cidr == "" {
+				continue
+			}
+
+			ipPool := calicoIPPool{cidr: cidr}
+			ipPool.ipipMode, _, _ = unstructured.NestedString(spec, "ipipMode")
+			ipPool.vxlanMode, _, _ = unstructured.NestedString(spec, "vxlanMode")
+
+			ipPools = append(ipPools, ipPool)
+		}
+
+		if len(ipPools) > 0 {
+			return ipPools, nil
+		}
 	}
 
 	return nil, nil
@@ -19,15 +19,21 @@ limitations under the License.
 package network_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/fake"
 	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
 	"github.com/submariner-io/submariner/pkg/cni"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("Calico Network", func() {
@@ -49,7 +55,7 @@ var _ = Describe("Calico Network", func() {
 	})
 
 	JustBeforeEach(func(ctx SpecContext) {
-		client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(initObjs...).Build()
+		client := fakeClient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(initObjs...).Build()
 		clusterNet, err = network.Discover(ctx, client, "")
 	})
 
@@ -91,4 +97,95 @@ var _ = Describe("Calico Network", func() {
 			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDR}))
 		})
 	})
+
+	When("Calico IPPools are available", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			initObjs = []client.Object{
+				calicoCfgMap,
+				fakeKubeAPIServerPod(),
+				fakeKubeControllerManagerPod(),
+				newCalicoIPPool("crd.projectcalico.org/v1", "default-ipv4-ippool", "10.244.0.0/16", false, "Never", "CrossSubnet"),
+				newCalicoIPPool("crd.projectcalico.org/v1", "disabled-ippool", "10.245.0.0/16", true, "Always", ""),
+				newCalicoIPPool("crd.projectcalico.org/v1", "default-ipv6-ippool", "fd00:10:244::/64", false, "", ""),
+			}
+
+			client := newTestClient(initObjs...)
+			clusterNet, err = network.Discover(ctx, client, "")
+		})
+
+		It("should return a ClusterNetwork with the enabled IPPool CIDRs and encapsulation", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Calico))
+			Expect(clusterNet.PodCIDRs).To(ConsistOf("10.244.0.0/16", "fd00:10:244::/64"))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceCalicoIPPool))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDR}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceKubeAPIServer))
+			Expect(clusterNet.NetworkPluginEvidence).To(Equal("ConfigMap calico-config"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CalicoIPIPModeSetting, "Never"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CalicoVXLANModeSetting, "CrossSubnet"))
+		})
+	})
+
+	When("Calico IPPools can't be listed", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			client := fake.NewReactingClient(newTestClient(calicoCfgMap, fakeKubeAPIServerPod(), fakeKubeControllerManagerPod())).
+				AddReactor(fake.List, &unstructured.UnstructuredList{}, func(obj interface{}) (bool, error) {
+					list := obj.(*unstructured.UnstructuredList)
+					if list.GroupVersionKind().Kind != "IPPoolList" {
+						return false, nil
+					}
+
+					return true, apierrors.NewForbidden(schema.GroupResource{Group: list.GroupVersionKind().Group, Resource: "ippools"},
+						"", errors.New("not allowed"))
+				})
+
+			clusterNet, err = network.Discover(ctx, client, "")
+		})
+
+		It("should fall back to the generic discovery", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Calico))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testPodCIDR}))
+			Expect(clusterNet.PluginSettings).To(BeEmpty())
+		})
+	})
+
+	When("only projectcalico.org IPPools are available", func() {
+		JustBeforeEach(func(ctx SpecContext) {
+			initObjs = []client.Object{
+				newCalicoIPPool("projectcalico.org/v3", "default-ipv4-ippool", "10.244.0.0/16", false, "Always", "Never"),
+			}
+
+			client := newTestClient(initObjs...)
+			clusterNet, err = network.Discover(ctx, client, "")
+		})
+
+		It("should return a ClusterNetwork with the IPPool CIDRs and encapsulation", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Calico))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.244.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CalicoIPIPModeSetting, "Always"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CalicoVXLANModeSetting, "Never"))
+		})
+	})
 })
+
+func newCalicoIPPool(apiVersion, name, cidr string, disabled bool, ipipMode, vxlanMode string) *unstructured.Unstructured {
+	ipPool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"cidr":      cidr,
+			"disabled":  disabled,
+			"ipipMode":  ipipMode,
+			"vxlanMode": vxlanMode,
+		},
+	}}
+	ipPool.SetAPIVersion(apiVersion)
+	ipPool.SetKind("IPPool")
+	ipPool.SetName(name)
+
+	return ipPool
+}
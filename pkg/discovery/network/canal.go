@@ -50,6 +50,7 @@ func discoverCanalFlannelNetwork(ctx context.Context, client controllerClient.Cl
 	}
 
 	clusterNetwork.NetworkPlugin = cni.CanalFlannel
+	clusterNetwork.NetworkPluginEvidence = evidence("DaemonSet", &daemonsets.Items[0])
 
 	return clusterNetwork, nil
 }
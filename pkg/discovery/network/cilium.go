@@ -0,0 +1,147 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Cilium plugin settings, named after the corresponding cilium-config keys.
+const (
+	CiliumIPAMSetting                 = "ipam"
+	CiliumKubeProxyReplacementSetting = "kube-proxy-replacement"
+)
+
+const ciliumConfigMapName = "cilium-config"
+
+// Cilium is deployed in kube-system by default, and in its own namespace by some installers.
+var ciliumNamespaces = []string{metav1.NamespaceSystem, "cilium"}
+
+var ciliumNodeGVK = schema.GroupVersionKind{
+	Group:   "cilium.io",
+	Version: "v2",
+	Kind:    "CiliumNodeList",
+}
+
+//nolint:nilnil // Intentional as the purpose is to discover.
+func discoverCiliumNetwork(ctx context.Context, client controllerClient.Client) (*ClusterNetwork, error) {
+	ciliumConfig, err := findCiliumConfigMap(ctx, client)
+	if err != nil || ciliumConfig == nil {
+		return nil, err
+	}
+
+	clusterNetwork := &ClusterNetwork{
+		NetworkPlugin:         Cilium,
+		NetworkPluginEvidence: evidence("ConfigMap", ciliumConfig),
+		PluginSettings: map[string]string{
+			CiliumIPAMSetting:                 ciliumConfig.Data["ipam"],
+			CiliumKubeProxyReplacementSetting: ciliumConfig.Data["kube-proxy-replacement"],
+		},
+	}
+
+	// In cluster-pool mode (the default), pod CIDRs are carved out of the configured pools. In other modes, such as
+	// kubernetes or multi-pool, they're inferred from the CIDRs assigned to each node.
+	switch ciliumConfig.Data["ipam"] {
+	case "", "cluster-pool":
+		var podCIDRs []string
+
+		for _, key := range []string{"cluster-pool-ipv4-cidr", "cluster-pool-ipv6-cidr"} {
+			podCIDRs = append(podCIDRs, strings.Fields(ciliumConfig.Data[key])...)
+		}
+
+		if len(podCIDRs) > 0 {
+			clusterNetwork.setPodCIDRs(discoveredCIDRs{cidrs: podCIDRs, source: SourcePluginConfig, confidence: ConfidenceHigh})
+		}
+	default:
+		podIPRanges, err := findCiliumNodePodCIDRs(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		clusterNetwork.setPodCIDRs(podIPRanges)
+	}
+
+	clusterIPRanges, err := findClusterIPRanges(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNetwork.setServiceCIDRs(clusterIPRanges)
+
+	return clusterNetwork, nil
+}
+
+//nolint:nilnil // Intentional as the purpose is to discover.
+func findCiliumConfigMap(ctx context.Context, client controllerClient.Client) (*corev1.ConfigMap, error) {
+	for _, namespace := range ciliumNamespaces {
+		configMap := &corev1.ConfigMap{}
+
+		err := client.Get(ctx, controllerClient.ObjectKey{Namespace: namespace, Name: ciliumConfigMapName}, configMap)
+		if err == nil {
+			return configMap, nil
+		}
+
+		if !resource.IsNotFoundErr(err) {
+			return nil, errors.Wrapf(err, "error retrieving the Cilium ConfigMap in namespace %q", namespace)
+		}
+	}
+
+	return nil, nil
+}
+
+// findCiliumNodePodCIDRs infers the pod CIDRs from the ranges assigned by Cilium to each node.
+func findCiliumNodePodCIDRs(ctx context.Context, client controllerClient.Client) (discoveredCIDRs, error) {
+	ciliumNodes := &unstructured.UnstructuredList{}
+	ciliumNodes.SetGroupVersionKind(ciliumNodeGVK)
+
+	err := client.List(ctx, ciliumNodes)
+	if resource.IsNotFoundErr(err) {
+		return discoveredCIDRs{}, nil
+	}
+
+	if err != nil {
+		return discoveredCIDRs{}, errors.Wrap(err, "error listing CiliumNodes")
+	}
+
+	nodes := make([]corev1.Node, len(ciliumNodes.Items))
+
+	for i := range ciliumNodes.Items {
+		nodes[i].Spec.PodCIDRs, _, err = unstructured.NestedStringSlice(ciliumNodes.Items[i].Object, "spec", "ipam", "podCIDRs")
+		if err != nil {
+			return discoveredCIDRs{}, errors.Wrapf(err, "error retrieving the pod CIDRs of CiliumNode %q",
+				ciliumNodes.Items[i].GetName())
+		}
+	}
+
+	podCIDRs, confidence := aggregateNodePodCIDRs(nodes)
+	if len(podCIDRs) == 0 {
+		return discoveredCIDRs{}, nil
+	}
+
+	return discoveredCIDRs{cidrs: podCIDRs, source: SourceCiliumNode, confidence: confidence}, nil
+}
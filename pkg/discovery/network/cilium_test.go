@@ -0,0 +1,121 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	testCiliumPodCIDRv4 = "10.0.0.0/8"
+	testCiliumPodCIDRv6 = "fd00::/104"
+)
+
+var _ = Describe("Cilium Network", func() {
+	When("the cilium ConfigMap specifies cluster pools", func() {
+		It("should return a ClusterNetwork with the plugin name, CIDRs and settings set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newCiliumConfigMap(metav1.NamespaceSystem, map[string]string{
+				"ipam":                   "cluster-pool",
+				"cluster-pool-ipv4-cidr": testCiliumPodCIDRv4,
+				"cluster-pool-ipv6-cidr": testCiliumPodCIDRv6,
+				"kube-proxy-replacement": "true",
+			}))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.Cilium))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testCiliumPodCIDRv4, testCiliumPodCIDRv6}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourcePluginConfig))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.NetworkPluginEvidence).To(Equal("ConfigMap kube-system/cilium-config"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CiliumIPAMSetting, "cluster-pool"))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CiliumKubeProxyReplacementSetting, "true"))
+		})
+	})
+
+	When("the cilium ConfigMap is in the cilium namespace", func() {
+		It("should return a ClusterNetwork with the plugin name and CIDRs set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newCiliumConfigMap("cilium", map[string]string{
+				"cluster-pool-ipv4-cidr": testCiliumPodCIDRv4,
+			}))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.Cilium))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testCiliumPodCIDRv4}))
+		})
+	})
+
+	When("the cilium IPAM mode isn't cluster-pool", func() {
+		It("should return a ClusterNetwork with the CIDRs aggregated from the CiliumNodes", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx,
+				newCiliumConfigMap(metav1.NamespaceSystem, map[string]string{
+					"ipam":                   "kubernetes",
+					"cluster-pool-ipv4-cidr": testCiliumPodCIDRv4,
+				}),
+				newCiliumNode("node1", "10.1.0.0/24", "fd00:1::/120"),
+				newCiliumNode("node2", "10.1.1.0/24", "fd00:1::100/120"))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.Cilium))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.1.0.0/23", "fd00:1::/119"}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceCiliumNode))
+			Expect(clusterNet.PodCIDRsConfidence).To(Equal(network.ConfidenceMedium))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.CiliumIPAMSetting, "kubernetes"))
+		})
+	})
+
+	When("the cilium ConfigMap does not exist", func() {
+		It("should return a ClusterNetwork with the generic plugin", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newCiliumNode("node1", "10.1.0.0/24"))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).NotTo(Equal(network.Cilium))
+		})
+	})
+})
+
+func newCiliumConfigMap(namespace string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cilium-config",
+			Namespace: namespace,
+		},
+		Data: data,
+	}
+}
+
+func newCiliumNode(name string, podCIDRs ...string) *unstructured.Unstructured {
+	cidrs := make([]interface{}, len(podCIDRs))
+	for i := range podCIDRs {
+		cidrs[i] = podCIDRs[i]
+	}
+
+	node := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ipam": map[string]interface{}{
+				"podCIDRs": cidrs,
+			},
+		},
+	}}
+	node.SetAPIVersion("cilium.io/v2")
+	node.SetKind("CiliumNode")
+	node.SetName(name)
+
+	return node
+}
@@ -42,10 +42,12 @@ func discoverFlannelNetwork(ctx context.Context, client controllerClient.Client)
 	}
 
 	volumes := make([]corev1.Volume, 0)
+	flannelEvidence := ""
 	// look for a daemonset matching "flannel"
 	for k := range daemonsets.Items {
 		if strings.Contains(daemonsets.Items[k].Name, "flannel") {
 			volumes = daemonsets.Items[k].Spec.Template.Spec.Volumes
+			flannelEvidence = evidence("DaemonSet", &daemonsets.Items[k])
 		}
 	}
 
@@ -59,21 +61,22 @@ func discoverFlannelNetwork(ctx context.Context, client controllerClient.Client)
 	}
 
 	clusterNetwork.NetworkPlugin = cni.Flannel
+	clusterNetwork.NetworkPluginEvidence = flannelEvidence
 
 	return clusterNetwork, nil
 }
 
 //nolint:nilnil // Intentional as the purpose is to discover.
 func extractCIDRsFromFlannelConfigMap(ctx context.Context, client controllerClient.Client, configMapName string) (*ClusterNetwork, error) {
-	var podCIDR *string
+	var podIPRanges discoveredCIDRs
 
 	if configMapName == "" {
-		podIPRange, err := findPodIPRange(ctx, client)
+		var err error
+
+		podIPRanges, err = findPodIPRanges(ctx, client)
 		if err != nil {
 			return nil, err
 		}
-
-		podCIDR = &podIPRange
 	} else {
 		// look for the configmap details using the configmap name discovered from the daemonset
 		cm := &corev1.ConfigMap{}
@@ -90,25 +93,24 @@ func extractCIDRsFromFlannelConfigMap(ctx context.Context, client controllerClie
 			return nil, errors.WithMessagef(err, "error retrieving the flannel ConfigMap %q", configMapName)
 		}
 
-		podCIDR = extractPodCIDRFromNetConfigJSON(cm)
+		podCIDR := extractPodCIDRFromNetConfigJSON(cm)
 		if podCIDR == nil {
 			return nil, nil
 		}
-	}
 
-	clusterNetwork := &ClusterNetwork{
-		PodCIDRs: []string{*podCIDR},
+		podIPRanges = discoveredCIDRs{cidrs: []string{*podCIDR}, source: SourcePluginConfig, confidence: ConfidenceHigh}
 	}
 
+	clusterNetwork := &ClusterNetwork{}
+	clusterNetwork.setPodCIDRs(podIPRanges)
+
 	// Try to detect the service CIDRs using the generic functions
-	clusterIPRange, err := findClusterIPRange(ctx, client)
+	clusterIPRanges, err := findClusterIPRanges(ctx, client)
 	if err != nil {
 		return nil, err
 	}
 
-	if clusterIPRange != "" {
-		clusterNetwork.ServiceCIDRs = []string{clusterIPRange}
-	}
+	clusterNetwork.setServiceCIDRs(clusterIPRanges)
 
 	return clusterNetwork, nil
 }
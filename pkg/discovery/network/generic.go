@@ -19,19 +19,61 @@ limitations under the License.
 package network
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/submariner/pkg/cni"
 	corev1 "k8s.io/api/core/v1"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const defaultServiceCIDRName = "kubernetes"
+
+// discoveredCIDRs are CIDRs found by discovery, along with where they were found and how reliable they are.
+type discoveredCIDRs struct {
+	cidrs      []string
+	source     Source
+	confidence Confidence
+}
+
+// clusterConfigCIDRs are the CIDRs recorded in a Kubernetes distribution's cluster configuration.
+type clusterConfigCIDRs struct {
+	podCIDRs     []string
+	serviceCIDRs []string
+}
+
+// clusterConfigSource finds the CIDRs recorded by a Kubernetes distribution.
+type clusterConfigSource struct {
+	name   string
+	source Source
+	find   func(ctx context.Context, client controllerClient.Client) (clusterConfigCIDRs, error)
+}
+
+// Kubeadm, k3s and RKE2 control planes don't necessarily run as pods whose parameters can be read, but they record their
+// configuration. RKE2 is checked before k3s since it's derived from it.
+var clusterConfigSources = []clusterConfigSource{
+	{name: "kubeadm", source: SourceKubeadmConfig, find: findKubeadmCIDRs},
+	{name: "RKE2", source: SourceRKE2Config, find: findRKE2CIDRs},
+	{name: "k3s", source: SourceK3sNodeArgs, find: findK3sCIDRs},
+}
+
+// ServiceCIDRs are GA in v1 and beta in v1beta1; the latter is disabled by default.
+var serviceCIDRGVKs = []schema.GroupVersionKind{
+	{Group: "networking.k8s.io", Version: "v1", Kind: "ServiceCIDRList"},
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "ServiceCIDRList"},
+}
+
 //nolint:nilnil // Intentional as the purpose is to discover.
 func discoverGenericNetwork(ctx context.Context, client controllerClient.Client) (*ClusterNetwork, error) {
 	clusterNetwork, err := discoverNetwork(ctx, client)
@@ -51,23 +93,19 @@ func discoverGenericNetwork(ctx context.Context, client controllerClient.Client)
 func discoverNetwork(ctx context.Context, client controllerClient.Client) (*ClusterNetwork, error) {
 	clusterNetwork := &ClusterNetwork{}
 
-	podIPRange, err := findPodIPRange(ctx, client)
+	podIPRanges, err := findPodIPRanges(ctx, client)
 	if err != nil {
 		return nil, err
 	}
 
-	if podIPRange != "" {
-		clusterNetwork.PodCIDRs = []string{podIPRange}
-	}
+	clusterNetwork.setPodCIDRs(podIPRanges)
 
-	clusterIPRange, err := findClusterIPRange(ctx, client)
+	clusterIPRanges, err := findClusterIPRanges(ctx, client)
 	if err != nil {
 		return nil, err
 	}
 
-	if clusterIPRange != "" {
-		clusterNetwork.ServiceCIDRs = []string{clusterIPRange}
-	}
+	clusterNetwork.setServiceCIDRs(clusterIPRanges)
 
 	if len(clusterNetwork.PodCIDRs) > 0 || len(clusterNetwork.ServiceCIDRs) > 0 {
 		return clusterNetwork, nil
@@ -76,18 +114,85 @@ func discoverNetwork(ctx context.Context, client controllerClient.Client) (*Clus
 	return nil, nil
 }
 
-func findClusterIPRange(ctx context.Context, client controllerClient.Client) (string, error) {
+// findClusterIPRanges returns the service CIDRs, from the most to the least reliable source. Creating an invalid Service
+// to find them from the resulting error is only a last resort since it relies on the API server's wording and needs
+// permission to create Services.
+func findClusterIPRanges(ctx context.Context, client controllerClient.Client) (discoveredCIDRs, error) {
+	if clusterIPRanges := findClusterIPRangesFromServiceCIDRs(ctx, client); len(clusterIPRanges) > 0 {
+		return discoveredCIDRs{cidrs: clusterIPRanges, source: SourceServiceCIDR}, nil
+	}
+
 	clusterIPRange, err := findClusterIPRangeFromApiserver(ctx, client)
 	if err != nil || clusterIPRange != "" {
-		return clusterIPRange, err
+		return discoveredCIDRs{cidrs: splitCIDRs(clusterIPRange), source: SourceKubeAPIServer}, err
+	}
+
+	if discovered := findClusterConfigCIDRs(ctx, client, func(c clusterConfigCIDRs) []string {
+		return c.serviceCIDRs
+	}); len(discovered.cidrs) > 0 {
+		return discovered, nil
 	}
 
 	clusterIPRange, err = findClusterIPRangeFromServiceCreation(ctx, client)
 	if err != nil || clusterIPRange != "" {
-		return clusterIPRange, err
+		return discoveredCIDRs{cidrs: splitCIDRs(clusterIPRange), source: SourceInvalidServiceError}, err
 	}
 
-	return "", nil
+	return discoveredCIDRs{}, nil
+}
+
+// findClusterIPRangesFromServiceCIDRs returns the CIDRs of the ServiceCIDR resources available since Kubernetes 1.31,
+// starting with the default one created from the API server's configuration. ServiceCIDRs which can't be listed, for
+// example because the operator isn't allowed to, are skipped so that the other sources are tried.
+func findClusterIPRangesFromServiceCIDRs(ctx context.Context, client controllerClient.Client) []string {
+	for _, gvk := range serviceCIDRGVKs {
+		serviceCIDRs := &unstructured.UnstructuredList{}
+		serviceCIDRs.SetGroupVersionKind(gvk)
+
+		err := client.List(ctx, serviceCIDRs)
+		if resource.IsNotFoundErr(err) {
+			continue
+		}
+
+		if err != nil {
+			log.Error(err, "Unable to list the ServiceCIDRs", "groupVersion", gvk.GroupVersion().String())
+			continue
+		}
+
+		order := func(serviceCIDR *unstructured.Unstructured) int {
+			if serviceCIDR.GetName() == defaultServiceCIDRName {
+				return 0
+			}
+
+			return 1
+		}
+
+		items := serviceCIDRs.Items
+		slices.SortStableFunc(items, func(a, b unstructured.Unstructured) int {
+			return cmp.Compare(order(&a), order(&b))
+		})
+
+		var clusterIPRanges []string
+
+		for i := range items {
+			if items[i].GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			cidrs, _, _ := unstructured.NestedStringSlice(items[i].Object, "spec", "cidrs")
+			for _, cidr := range cidrs {
+				if !slices.Contains(clusterIPRanges, cidr) {
+					clusterIPRanges = append(clusterIPRanges, cidr)
+				}
+			}
+		}
+
+		if len(clusterIPRanges) > 0 {
+			return clusterIPRanges
+		}
+	}
+
+	return nil
 }
 
 func findClusterIPRangeFromApiserver(ctx context.Context, client controllerClient.Client) (string, error) {
@@ -152,23 +257,48 @@ func parseServiceCIDRFrom(msg string) (string, error) {
 	return match[1], nil
 }
 
-func findPodIPRange(ctx context.Context, client controllerClient.Client) (string, error) {
+// findPodIPRanges returns the pod CIDRs and how confident the discovery is in them. CIDRs read from the cluster's
+// configuration are exact; those inferred from the ranges assigned to nodes may be narrower than the cluster's.
+func findPodIPRanges(ctx context.Context, client controllerClient.Client) (discoveredCIDRs, error) {
 	podIPRange, err := findPodIPRangeKubeController(ctx, client)
 	if err != nil || podIPRange != "" {
-		return podIPRange, err
+		return discoveredCIDRs{cidrs: splitCIDRs(podIPRange), source: SourceKubeControllerManager, confidence: ConfidenceHigh}, err
 	}
 
 	podIPRange, err = findPodIPRangeKubeProxy(ctx, client)
 	if err != nil || podIPRange != "" {
-		return podIPRange, err
+		return discoveredCIDRs{cidrs: splitCIDRs(podIPRange), source: SourceKubeProxy, confidence: ConfidenceHigh}, err
 	}
 
-	podIPRange, err = findPodIPRangeFromNodeSpec(ctx, client)
-	if err != nil || podIPRange != "" {
-		return podIPRange, err
+	if discovered := findClusterConfigCIDRs(ctx, client, func(c clusterConfigCIDRs) []string {
+		return c.podCIDRs
+	}); len(discovered.cidrs) > 0 {
+		discovered.confidence = ConfidenceHigh
+		return discovered, nil
 	}
 
-	return "", nil
+	return findPodIPRangesFromNodeSpec(ctx, client)
+}
+
+// findClusterConfigCIDRs returns the CIDRs selected by cidrsOf from the first cluster configuration source which has any.
+// Sources which fail, for example because their configuration can't be read or parsed, are skipped so that the other
+// sources are tried.
+func findClusterConfigCIDRs(ctx context.Context, client controllerClient.Client,
+	cidrsOf func(clusterConfigCIDRs) []string,
+) discoveredCIDRs {
+	for _, configSource := range clusterConfigSources {
+		cidrs, err := configSource.find(ctx, client)
+		if err != nil {
+			log.Error(err, "Unable to read the cluster configuration", "distribution", configSource.name)
+			continue
+		}
+
+		if found := cidrsOf(cidrs); len(found) > 0 {
+			return discoveredCIDRs{cidrs: found, source: configSource.source}
+		}
+	}
+
+	return discoveredCIDRs{}
 }
 
 func findPodIPRangeKubeController(ctx context.Context, client controllerClient.Client) (string, error) {
@@ -179,24 +309,31 @@ func findPodIPRangeKubeProxy(ctx context.Context, client controllerClient.Client
 	return FindPodCommandParameter(ctx, client, "component=kube-proxy", "--cluster-cidr")
 }
 
-func findPodIPRangeFromNodeSpec(ctx context.Context, client controllerClient.Client) (string, error) {
+func findPodIPRangesFromNodeSpec(ctx context.Context, client controllerClient.Client) (discoveredCIDRs, error) {
 	nodes := &corev1.NodeList{}
 
 	err := client.List(ctx, nodes)
 	if err != nil {
-		return "", errors.WithMessagef(err, "error listing nodes")
+		return discoveredCIDRs{}, errors.WithMessagef(err, "error listing nodes")
 	}
 
-	return parseToPodCidr(nodes.Items)
+	podCIDRs, confidence := aggregateNodePodCIDRs(nodes.Items)
+	if len(podCIDRs) == 0 {
+		return discoveredCIDRs{}, nil
+	}
+
+	return discoveredCIDRs{cidrs: podCIDRs, source: SourceNodeSpec, confidence: confidence}, nil
 }
 
-func parseToPodCidr(nodes []corev1.Node) (string, error) {
-	// In K8s, each node is typically assigned a unique PodCIDR range for the pods that run on that node.
-	// Each node's PodCIDR is used to allocate IP addresses to the pods scheduled on that node. Only if
-	// the cluster is a single node deployment, we should rely on the node.Spec.PodCIDR as podCIDR of the cluster.
-	if len(nodes) == 1 {
-		return nodes[0].Spec.PodCIDR, nil
+// splitCIDRs splits a comma-separated list of CIDRs, as used for dual-stack configurations.
+func splitCIDRs(cidrs string) []string {
+	var result []string
+
+	for _, cidr := range strings.Split(cidrs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			result = append(result, cidr)
+		}
 	}
 
-	return "", nil
+	return result
 }
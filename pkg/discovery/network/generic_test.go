@@ -20,6 +20,7 @@ package network_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -30,7 +31,10 @@ import (
 	"github.com/submariner-io/submariner-operator/pkg/names"
 	"github.com/submariner-io/submariner/pkg/cni"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
 	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -122,6 +126,8 @@ var _ = Describe("Generic Network", func() {
 
 		It("Should return the ClusterNetwork structure with pod CIDR", func() {
 			Expect(clusterNet.PodCIDRs).To(Equal([]string{testPodCIDR}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceKubeControllerManager))
+			Expect(clusterNet.PodCIDRsConfidence).To(Equal(network.ConfidenceHigh))
 		})
 
 		It("Should identify the networkplugin as generic", func() {
@@ -130,6 +136,7 @@ var _ = Describe("Generic Network", func() {
 
 		It("Should return the ClusterNetwork structure with the service CIDR", func() {
 			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceInvalidServiceError))
 		})
 	})
 
@@ -158,6 +165,7 @@ var _ = Describe("Generic Network", func() {
 
 		It("Should return the ClusterNetwork structure with PodCIDR", func() {
 			Expect(clusterNet.PodCIDRs).To(Equal([]string{testPodCIDR}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceKubeProxy))
 		})
 
 		It("Should identify the networkplugin as generic", func() {
@@ -180,6 +188,7 @@ var _ = Describe("Generic Network", func() {
 
 		It("Should return the ClusterNetwork structure with ServiceCIDRs", func() {
 			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDR}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceKubeAPIServer))
 		})
 
 		It("Should identify the networkplugin as generic", func() {
@@ -211,6 +220,73 @@ var _ = Describe("Generic Network", func() {
 		})
 	})
 
+	When("There are ServiceCIDR resources", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakeKubeAPIServerPod(),
+				fakeServiceCIDR("networking.k8s.io/v1", "extra", false, "10.100.0.0/16"),
+				fakeServiceCIDR("networking.k8s.io/v1", "kubernetes", false, "10.96.0.0/16", "fd00:10:96::/112"),
+				fakeServiceCIDR("networking.k8s.io/v1", "removed", true, "10.200.0.0/16"),
+			)
+			Expect(clusterNet).NotTo(BeNil())
+		})
+
+		It("Should return the ServiceCIDRs with the default one first, ignoring those being deleted", func() {
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.96.0.0/16", "fd00:10:96::/112", "10.100.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceServiceCIDR))
+		})
+	})
+
+	When("There are only v1beta1 ServiceCIDR resources", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakeServiceCIDR("networking.k8s.io/v1beta1", "kubernetes", false, "10.96.0.0/16"),
+			)
+			Expect(clusterNet).NotTo(BeNil())
+		})
+
+		It("Should return the ServiceCIDRs", func() {
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.96.0.0/16"}))
+		})
+	})
+
+	When("ServiceCIDR resources can't be listed", func() {
+		BeforeEach(func(ctx SpecContext) {
+			client := fake.NewReactingClient(newTestClient(fakeKubeAPIServerPod(),
+				fakeServiceCIDR("networking.k8s.io/v1", "kubernetes", false, "10.96.0.0/16"))).
+				AddReactor(fake.List, &unstructured.UnstructuredList{}, fake.FailingReaction(apierrors.NewForbidden(
+					schema.GroupResource{Group: "networking.k8s.io", Resource: "servicecidrs"}, "", errors.New("not allowed"))))
+
+			var err error
+
+			clusterNet, err = network.Discover(ctx, client, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterNet).NotTo(BeNil())
+		})
+
+		It("Should return the ServiceCIDRs from the next source", func() {
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDR}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceKubeAPIServer))
+		})
+	})
+
+	When("There is a dual-stack kubeapi pod", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakePod("kube-apiserver", []string{"kube-apiserver", "--service-cluster-ip-range=" + testServiceCIDR + ",fd00::/112"},
+					[]corev1.EnvVar{}),
+			)
+			Expect(clusterNet).NotTo(BeNil())
+		})
+
+		It("Should return both ServiceCIDRs", func() {
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDR, "fd00::/112"}))
+		})
+	})
+
 	When("No pod CIDR information exists on any node", func() {
 		BeforeEach(func(ctx SpecContext) {
 			clusterNet = testDiscoverGenericWith(
@@ -243,6 +319,8 @@ var _ = Describe("Generic Network", func() {
 
 		It("Should return the ClusterNetwork structure with the pod CIDR", func() {
 			Expect(clusterNet.PodCIDRs).To(Equal([]string{testPodCIDR}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceNodeSpec))
+			Expect(clusterNet.PodCIDRsConfidence).To(Equal(network.ConfidenceLow))
 		})
 
 		It("Should identify the networkplugin as generic", func() {
@@ -268,6 +346,65 @@ var _ = Describe("Generic Network", func() {
 		})
 	})
 
+	When("Consistent pod CIDRs are assigned to the nodes of a multi node cluster", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakeNodeWithPodCIDRs("node1", "10.244.0.0/24"),
+				fakeNodeWithPodCIDRs("node2", "10.244.1.0/24"),
+				fakeNodeWithPodCIDRs("node3", "10.244.2.0/24"),
+				fakeNode("node4", ""),
+			)
+		})
+
+		It("Should return the smallest supernet covering them with a medium confidence", func() {
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.244.0.0/22"}))
+			Expect(clusterNet.PodCIDRsConfidence).To(Equal(network.ConfidenceMedium))
+		})
+	})
+
+	When("Consistent dual-stack pod CIDRs are assigned to the nodes of a multi node cluster", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakeNodeWithPodCIDRs("node1", "fd00:10:244::/64", "10.244.0.0/24"),
+				fakeNodeWithPodCIDRs("node2", "fd00:10:244:1::/64", "10.244.1.0/24"),
+			)
+		})
+
+		It("Should return a supernet per IP family", func() {
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"fd00:10:244::/63", "10.244.0.0/23"}))
+		})
+	})
+
+	When("Pod CIDRs of different sizes are assigned to the nodes of a multi node cluster", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakeNodeWithPodCIDRs("node1", "10.244.0.0/24"),
+				fakeNodeWithPodCIDRs("node2", "10.244.2.0/23"),
+			)
+		})
+
+		It("Should return the ClusterNetwork structure with empty PodCIDRs", func() {
+			Expect(clusterNet.PodCIDRs).To(BeEmpty())
+		})
+	})
+
+	When("Pod CIDRs of different IP families are assigned to the nodes of a multi node cluster", func() {
+		BeforeEach(func(ctx SpecContext) {
+			clusterNet = testDiscoverGenericWith(
+				ctx,
+				fakeNodeWithPodCIDRs("node1", "10.244.0.0/24", "fd00:10:244::/64"),
+				fakeNodeWithPodCIDRs("node2", "10.244.1.0/24"),
+			)
+		})
+
+		It("Should return the ClusterNetwork structure with empty PodCIDRs", func() {
+			Expect(clusterNet.PodCIDRs).To(BeEmpty())
+		})
+	})
+
 	When("Both pod and service CIDR information exists", func() {
 		BeforeEach(func(ctx SpecContext) {
 			clusterNet = testDiscoverGenericWith(
@@ -0,0 +1,95 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	k3sNodeArgsAnnotation = "k3s.io/node-args"
+	k3sServerCommand      = "server"
+	k3sDefaultClusterCIDR = "10.42.0.0/16"
+	k3sDefaultServiceCIDR = "10.43.0.0/16"
+)
+
+// findK3sCIDRs returns the CIDRs k3s servers were started with. k3s records each node's arguments, including those from
+// its configuration file, in an annotation.
+func findK3sCIDRs(ctx context.Context, client controllerClient.Client) (clusterConfigCIDRs, error) {
+	return findNodeArgsCIDRs(ctx, client, k3sNodeArgsAnnotation)
+}
+
+// findNodeArgsCIDRs returns the CIDRs in the server arguments recorded in the given node annotation by k3s and RKE2,
+// which default to the same values.
+func findNodeArgsCIDRs(ctx context.Context, client controllerClient.Client, annotation string) (clusterConfigCIDRs, error) {
+	nodes := &corev1.NodeList{}
+
+	err := client.List(ctx, nodes)
+	if err != nil {
+		return clusterConfigCIDRs{}, errors.Wrapf(err, "error listing nodes to find the %q annotation", annotation)
+	}
+
+	for i := range nodes.Items {
+		nodeArgs, found := nodes.Items[i].Annotations[annotation]
+		if !found {
+			continue
+		}
+
+		var args []string
+
+		err := json.Unmarshal([]byte(nodeArgs), &args)
+		if err != nil {
+			return clusterConfigCIDRs{}, errors.Wrapf(err, "error parsing the %q annotation of node %q", annotation,
+				nodes.Items[i].Name)
+		}
+
+		// Agents aren't given the cluster's CIDRs.
+		if len(args) == 0 || args[0] != k3sServerCommand {
+			continue
+		}
+
+		return clusterConfigCIDRs{
+			podCIDRs:     splitCIDRs(valueOrDefault(k3sArgValue(args, "--cluster-cidr"), k3sDefaultClusterCIDR)),
+			serviceCIDRs: splitCIDRs(valueOrDefault(k3sArgValue(args, "--service-cidr"), k3sDefaultServiceCIDR)),
+		}, nil
+	}
+
+	return clusterConfigCIDRs{}, nil
+}
+
+// k3sArgValue returns the value of the given flag, which is either in the same argument or in the next one.
+func k3sArgValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+
+		if value, found := strings.CutPrefix(arg, flag+"="); found {
+			return value
+		}
+	}
+
+	return ""
+}
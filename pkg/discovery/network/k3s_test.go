@@ -0,0 +1,74 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("K3s Network", func() {
+	When("a k3s server was started with CIDR arguments", func() {
+		It("should return a ClusterNetwork with the CIDRs set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx,
+				newNodeWithArgs("agent", "k3s.io/node-args", `["agent","--server","https://10.0.0.1:6443"]`),
+				newNodeWithArgs("server", "k3s.io/node-args",
+					`["server","--cluster-cidr","10.52.0.0/16,fd00:42::/56","--service-cidr=10.53.0.0/16","--disable","traefik"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.52.0.0/16", "fd00:42::/56"}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.53.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceK3sNodeArgs))
+		})
+	})
+
+	When("a k3s server was started without CIDR arguments", func() {
+		It("should return a ClusterNetwork with the default k3s CIDRs", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newNodeWithArgs("server", "k3s.io/node-args", `["server"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.42.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.43.0.0/16"}))
+		})
+	})
+
+	When("there are only k3s agents", func() {
+		It("should not use the default k3s CIDRs", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newNodeWithArgs("agent", "k3s.io/node-args", `["agent"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+		})
+	})
+
+	When("the k3s node arguments can't be parsed", func() {
+		It("should fall back to the next source", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newNodeWithArgs("server", "k3s.io/node-args", "server"))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceInvalidServiceError))
+		})
+	})
+})
+
+func newNodeWithArgs(name, annotation, args string) *corev1.Node {
+	node := fakeNode(name, "")
+	node.Annotations = map[string]string{annotation: args}
+
+	return node
+}
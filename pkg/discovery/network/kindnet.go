@@ -33,21 +33,23 @@ func discoverKindNetwork(ctx context.Context, client controllerClient.Client) (*
 	}
 
 	clusterNetwork := &ClusterNetwork{
-		NetworkPlugin: cni.KindNet,
+		NetworkPlugin:         cni.KindNet,
+		NetworkPluginEvidence: evidence("Pod", kindNetPod),
 	}
 
 	for i := range kindNetPod.Spec.Containers {
 		for _, envVar := range kindNetPod.Spec.Containers[i].Env {
 			if envVar.Name == "POD_SUBNET" {
 				clusterNetwork.PodCIDRs = []string{envVar.Value}
+				clusterNetwork.PodCIDRsSource = SourcePluginConfig
 				break
 			}
 		}
 	}
 
-	clusterIPRange, err := findClusterIPRange(ctx, client)
-	if err == nil && clusterIPRange != "" {
-		clusterNetwork.ServiceCIDRs = []string{clusterIPRange}
+	clusterIPRanges, err := findClusterIPRanges(ctx, client)
+	if err == nil {
+		clusterNetwork.setServiceCIDRs(clusterIPRanges)
 	}
 
 	return clusterNetwork, nil
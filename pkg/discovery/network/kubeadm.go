@@ -0,0 +1,68 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	kubeadmConfigMapName           = "kubeadm-config"
+	kubeadmClusterConfigurationKey = "ClusterConfiguration"
+)
+
+type kubeadmClusterConfiguration struct {
+	Networking struct {
+		PodSubnet     string `json:"podSubnet"`
+		ServiceSubnet string `json:"serviceSubnet"`
+	} `json:"networking"`
+}
+
+// findKubeadmCIDRs returns the CIDRs in the ClusterConfiguration stored by kubeadm when it initializes a cluster.
+func findKubeadmCIDRs(ctx context.Context, client controllerClient.Client) (clusterConfigCIDRs, error) {
+	configMap := &corev1.ConfigMap{}
+
+	err := client.Get(ctx, controllerClient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: kubeadmConfigMapName}, configMap)
+	if apierrors.IsNotFound(err) {
+		return clusterConfigCIDRs{}, nil
+	}
+
+	if err != nil {
+		return clusterConfigCIDRs{}, errors.Wrapf(err, "error retrieving the kubeadm ConfigMap %q", kubeadmConfigMapName)
+	}
+
+	clusterConfiguration := kubeadmClusterConfiguration{}
+
+	err = yaml.Unmarshal([]byte(configMap.Data[kubeadmClusterConfigurationKey]), &clusterConfiguration)
+	if err != nil {
+		return clusterConfigCIDRs{}, errors.Wrapf(err, "error parsing the kubeadm %s", kubeadmClusterConfigurationKey)
+	}
+
+	return clusterConfigCIDRs{
+		podCIDRs:     splitCIDRs(clusterConfiguration.Networking.PodSubnet),
+		serviceCIDRs: splitCIDRs(clusterConfiguration.Networking.ServiceSubnet),
+	}, nil
+}
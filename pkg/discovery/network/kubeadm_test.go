@@ -0,0 +1,88 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	"github.com/submariner-io/submariner/pkg/cni"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Kubeadm Network", func() {
+	When("the kubeadm ClusterConfiguration specifies the networking subnets", func() {
+		It("should return a ClusterNetwork with the CIDRs set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newKubeadmConfigMap(`
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+networking:
+  dnsDomain: cluster.local
+  podSubnet: 10.244.0.0/16,fd00:10:244::/56
+  serviceSubnet: 10.96.0.0/12
+`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Generic))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.244.0.0/16", "fd00:10:244::/56"}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.96.0.0/12"}))
+		})
+	})
+
+	When("the kube-controller-manager and kube-apiserver parameters are available", func() {
+		It("should prefer them", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, fakeKubeAPIServerPod(), fakeKubeControllerManagerPod(),
+				newKubeadmConfigMap("networking: {podSubnet: 10.244.0.0/16, serviceSubnet: 10.96.0.0/12}"))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testPodCIDR}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDR}))
+		})
+	})
+
+	When("the kubeadm ClusterConfiguration doesn't specify a pod subnet", func() {
+		It("should return a ClusterNetwork with only the service CIDRs", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newKubeadmConfigMap("networking: {serviceSubnet: 10.96.0.0/12}"))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(BeEmpty())
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.96.0.0/12"}))
+		})
+	})
+
+	When("the kubeadm ClusterConfiguration can't be parsed", func() {
+		It("should fall back to the next source", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newKubeadmConfigMap("networking: ["),
+				newNodeWithArgs("server", "k3s.io/node-args", `["server","--service-cidr","10.53.0.0/16"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.53.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceK3sNodeArgs))
+		})
+	})
+})
+
+func newKubeadmConfigMap(clusterConfiguration string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubeadm-config",
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string]string{
+			"ClusterConfiguration": clusterConfiguration,
+		},
+	}
+}
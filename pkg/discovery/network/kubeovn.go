@@ -0,0 +1,117 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubeOVNNetworkTypeSetting is the Kube-OVN plugin setting giving the network type of the default subnet, geneve,
+// vxlan, stt or vlan, as configured in the kube-ovn-controller --network-type parameter.
+const KubeOVNNetworkTypeSetting = "networkType"
+
+const (
+	kubeOVNDefaultSubnetName  = "ovn-default"
+	kubeOVNDefaultNetworkType = "geneve"
+	kubeOVNControllerSelector = "app=kube-ovn-controller"
+)
+
+var kubeOVNSubnetGVK = schema.GroupVersionKind{
+	Group:   "kubeovn.io",
+	Version: "v1",
+	Kind:    "SubnetList",
+}
+
+//nolint:nilnil // Intentional as the purpose is to discover.
+func discoverKubeOVNNetwork(ctx context.Context, client controllerClient.Client) (*ClusterNetwork, error) {
+	subnet, err := findKubeOVNDefaultSubnet(ctx, client)
+	if err != nil || subnet == nil {
+		return nil, err
+	}
+
+	cidrBlock, _, err := unstructured.NestedString(subnet.Object, "spec", "cidrBlock")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving the CIDR block of Kube-OVN Subnet %q", subnet.GetName())
+	}
+
+	networkType, err := FindPodCommandParameter(ctx, client, kubeOVNControllerSelector, "--network-type")
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNetwork := &ClusterNetwork{
+		NetworkPlugin:         KubeOVN,
+		NetworkPluginEvidence: evidence("Subnet.kubeovn.io", subnet),
+		PodCIDRs:              splitCIDRs(cidrBlock),
+		PodCIDRsSource:        SourcePluginConfig,
+		PluginSettings: map[string]string{
+			KubeOVNNetworkTypeSetting: valueOrDefault(networkType, kubeOVNDefaultNetworkType),
+		},
+	}
+
+	serviceCIDRs, err := FindPodCommandParameter(ctx, client, kubeOVNControllerSelector, "--service-cluster-ip-range")
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNetwork.ServiceCIDRs = splitCIDRs(serviceCIDRs)
+	if len(clusterNetwork.ServiceCIDRs) > 0 {
+		clusterNetwork.ServiceCIDRsSource = SourcePluginConfig
+	}
+
+	return clusterNetwork, nil
+}
+
+// findKubeOVNDefaultSubnet returns the Subnet marked as the default one, falling back to the one created by default.
+//
+//nolint:nilnil // Intentional as the purpose is to find.
+func findKubeOVNDefaultSubnet(ctx context.Context, client controllerClient.Client) (*unstructured.Unstructured, error) {
+	subnets := &unstructured.UnstructuredList{}
+	subnets.SetGroupVersionKind(kubeOVNSubnetGVK)
+
+	err := client.List(ctx, subnets)
+	if resource.IsNotFoundErr(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing Kube-OVN Subnets")
+	}
+
+	var fallback *unstructured.Unstructured
+
+	for i := range subnets.Items {
+		isDefault, _, _ := unstructured.NestedBool(subnets.Items[i].Object, "spec", "default")
+		if isDefault {
+			return &subnets.Items[i], nil
+		}
+
+		if subnets.Items[i].GetName() == kubeOVNDefaultSubnetName {
+			fallback = &subnets.Items[i]
+		}
+	}
+
+	return fallback, nil
+}
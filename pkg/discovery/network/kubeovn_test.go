@@ -0,0 +1,89 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	"github.com/submariner-io/submariner/pkg/cni"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	testKubeOVNPodCIDRv4     = "10.16.0.0/16"
+	testKubeOVNPodCIDRv6     = "fd00:10:16::/64"
+	testKubeOVNServiceCIDRv4 = "10.96.0.0/12"
+	testKubeOVNServiceCIDRv6 = "fd00:10:96::/112"
+)
+
+var _ = Describe("Kube-OVN Network", func() {
+	When("the default Subnet and the kube-ovn-controller exist", func() {
+		It("should return a ClusterNetwork with the plugin name, CIDRs and settings set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx,
+				newKubeOVNSubnet("join", "100.64.0.0/16", false),
+				newKubeOVNSubnet("ovn-default", testKubeOVNPodCIDRv4+","+testKubeOVNPodCIDRv6, true),
+				fakePodWithArg("kube-ovn-controller", []string{"/kube-ovn/start-controller.sh"},
+					"--service-cluster-ip-range="+testKubeOVNServiceCIDRv4+","+testKubeOVNServiceCIDRv6),
+			)
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.KubeOVN))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testKubeOVNPodCIDRv4, testKubeOVNPodCIDRv6}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testKubeOVNServiceCIDRv4, testKubeOVNServiceCIDRv6}))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.KubeOVNNetworkTypeSetting, "geneve"))
+		})
+	})
+
+	When("no Subnet is marked as the default", func() {
+		It("should use the ovn-default Subnet", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx,
+				newKubeOVNSubnet("join", "100.64.0.0/16", false),
+				newKubeOVNSubnet("ovn-default", testKubeOVNPodCIDRv4, false),
+				fakePodWithArg("kube-ovn-controller", []string{"/kube-ovn/start-controller.sh"}, "--network-type=vlan"),
+			)
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(network.KubeOVN))
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{testKubeOVNPodCIDRv4}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.PluginSettings).To(HaveKeyWithValue(network.KubeOVNNetworkTypeSetting, "vlan"))
+		})
+	})
+
+	When("there is no default Subnet", func() {
+		It("should return a ClusterNetwork with the generic plugin", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newKubeOVNSubnet("join", "100.64.0.0/16", false))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.NetworkPlugin).To(Equal(cni.Generic))
+		})
+	})
+})
+
+func newKubeOVNSubnet(name, cidrBlock string, isDefault bool) *unstructured.Unstructured {
+	subnet := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"cidrBlock": cidrBlock,
+			"default":   isDefault,
+		},
+	}}
+	subnet.SetAPIVersion("kubeovn.io/v1")
+	subnet.SetKind("Subnet")
+	subnet.SetName(name)
+
+	return subnet
+}
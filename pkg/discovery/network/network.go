@@ -19,24 +19,76 @@ limitations under the License.
 package network
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/submariner-io/submariner-operator/api/v1alpha1"
 	"github.com/submariner-io/submariner-operator/pkg/names"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
 )
 
+var log = logf.Log.WithName("network-discovery")
+
+// Network plugins which aren't defined by Submariner's cni package.
+const (
+	Antrea  = "antrea"
+	Cilium  = "cilium"
+	KubeOVN = "kube-ovn"
+)
+
+// Confidence indicates how reliable discovered CIDRs are.
+type Confidence string
+
+const (
+	// ConfidenceHigh means the CIDRs were read from the cluster's configuration.
+	ConfidenceHigh Confidence = "High"
+	// ConfidenceMedium means the CIDRs were inferred from the ranges assigned to several nodes; they may be narrower than
+	// the cluster's if not all of its range has been assigned yet.
+	ConfidenceMedium Confidence = "Medium"
+	// ConfidenceLow means the CIDRs were inferred from the range assigned to a single node.
+	ConfidenceLow Confidence = "Low"
+)
+
+// Source identifies where discovered CIDRs were found.
+type Source string
+
+const (
+	SourceOpenShiftNetwork      Source = "OpenShiftNetworkCR"
+	SourcePluginConfig          Source = "PluginConfig"
+	SourceCalicoIPPool          Source = "CalicoIPPool"
+	SourceCiliumNode            Source = "CiliumNode"
+	SourceServiceCIDR           Source = "ServiceCIDR"
+	SourceKubeAPIServer         Source = "KubeAPIServerFlag"
+	SourceKubeControllerManager Source = "KubeControllerManagerFlag"
+	SourceKubeProxy             Source = "KubeProxyFlag"
+	SourceKubeadmConfig         Source = "KubeadmConfig"
+	SourceRKE2Config            Source = "RKE2Config"
+	SourceK3sNodeArgs           Source = "K3sNodeArgs"
+	SourceNodeSpec              Source = "NodeSpec"
+	SourceInvalidServiceError   Source = "InvalidServiceError"
+)
+
+// ClusterNetwork holds the discovered network details, along with where they were found. NetworkPluginEvidence describes
+// the resource the network plugin was detected from.
 type ClusterNetwork struct {
-	PodCIDRs         []string
-	ServiceCIDRs     []string
-	NetworkPlugin    string
-	GlobalCIDR       string
-	ClustersetIPCIDR string
-	PluginSettings   map[string]string
+	PodCIDRs              []string          `json:"podCIDRs,omitempty"`
+	PodCIDRsSource        Source            `json:"podCIDRsSource,omitempty"`
+	PodCIDRsConfidence    Confidence        `json:"podCIDRsConfidence,omitempty"`
+	ServiceCIDRs          []string          `json:"serviceCIDRs,omitempty"`
+	ServiceCIDRsSource    Source            `json:"serviceCIDRsSource,omitempty"`
+	NetworkPlugin         string            `json:"networkPlugin,omitempty"`
+	NetworkPluginEvidence string            `json:"networkPluginEvidence,omitempty"`
+	GlobalCIDR            string            `json:"globalCIDR,omitempty"`
+	ClustersetIPCIDR      string            `json:"clustersetIPCIDR,omitempty"`
+	PluginSettings        map[string]string `json:"pluginSettings,omitempty"`
 }
 
 func (cn *ClusterNetwork) Show() {
@@ -44,9 +96,27 @@ func (cn *ClusterNetwork) Show() {
 		fmt.Println("    No network details discovered")
 	} else {
 		fmt.Printf("        Network plugin:  %s\n", cn.NetworkPlugin)
+
+		if cn.NetworkPluginEvidence != "" {
+			fmt.Printf("        Detected from:   %s\n", cn.NetworkPluginEvidence)
+		}
+
 		fmt.Printf("        Service CIDRs:   %v\n", cn.ServiceCIDRs)
+
+		if cn.ServiceCIDRsSource != "" {
+			fmt.Printf("        Service source:  %s\n", cn.ServiceCIDRsSource)
+		}
+
 		fmt.Printf("        Cluster CIDRs:   %v\n", cn.PodCIDRs)
 
+		if cn.PodCIDRsSource != "" {
+			fmt.Printf("        Cluster source:  %s\n", cn.PodCIDRsSource)
+		}
+
+		if cn.PodCIDRsConfidence != "" && cn.PodCIDRsConfidence != ConfidenceHigh {
+			fmt.Printf("        CIDR confidence: %v\n", cn.PodCIDRsConfidence)
+		}
+
 		if cn.GlobalCIDR != "" {
 			fmt.Printf("        Global CIDR:     %v\n", cn.GlobalCIDR)
 		}
@@ -54,14 +124,69 @@ func (cn *ClusterNetwork) Show() {
 		if cn.ClustersetIPCIDR != "" {
 			fmt.Printf("        ClustersetIP CIDR:     %v\n", cn.ClustersetIPCIDR)
 		}
+
+		if len(cn.PluginSettings) > 0 {
+			fmt.Printf("        Plugin settings: %v\n", cn.PluginSettings)
+		}
+	}
+}
+
+// ShowAs prints the network details in the given format, json or yaml.
+func (cn *ClusterNetwork) ShowAs(format string) error {
+	var (
+		output []byte
+		err    error
+	)
+
+	switch format {
+	case "json":
+		output, err = json.MarshalIndent(cn, "", "  ")
+		output = append(output, '\n')
+	case "yaml":
+		output, err = yaml.Marshal(cn)
+	default:
+		return fmt.Errorf("unsupported output format %q, expected json or yaml", format)
 	}
+
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the network details")
+	}
+
+	fmt.Print(string(output))
+
+	return nil
 }
 
 func (cn *ClusterNetwork) Log(logger logr.Logger) {
 	logger.Info("Discovered K8s network details",
 		"plugin", cn.NetworkPlugin,
+		"pluginEvidence", cn.NetworkPluginEvidence,
 		"clusterCIDRs", cn.PodCIDRs,
-		"serviceCIDRs", cn.ServiceCIDRs)
+		"clusterCIDRsSource", cn.PodCIDRsSource,
+		"clusterCIDRsConfidence", cn.PodCIDRsConfidence,
+		"serviceCIDRs", cn.ServiceCIDRs,
+		"serviceCIDRsSource", cn.ServiceCIDRsSource,
+		"pluginSettings", cn.PluginSettings)
+}
+
+func (cn *ClusterNetwork) setPodCIDRs(discovered discoveredCIDRs) {
+	cn.PodCIDRs = discovered.cidrs
+	cn.PodCIDRsSource = discovered.source
+	cn.PodCIDRsConfidence = discovered.confidence
+}
+
+func (cn *ClusterNetwork) setServiceCIDRs(discovered discoveredCIDRs) {
+	cn.ServiceCIDRs = discovered.cidrs
+	cn.ServiceCIDRsSource = discovered.source
+}
+
+// evidence describes a resource a network plugin was detected from.
+func evidence(kind string, obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return kind + " " + obj.GetName()
+	}
+
+	return kind + " " + obj.GetNamespace() + "/" + obj.GetName()
 }
 
 func (cn *ClusterNetwork) IsComplete() bool {
@@ -80,10 +205,13 @@ func Discover(ctx context.Context, client controllerClient.Client, operatorNames
 			if genericNet != nil {
 				if len(discovery.ServiceCIDRs) == 0 {
 					discovery.ServiceCIDRs = genericNet.ServiceCIDRs
+					discovery.ServiceCIDRsSource = genericNet.ServiceCIDRsSource
 				}
 
 				if len(discovery.PodCIDRs) == 0 {
 					discovery.PodCIDRs = genericNet.PodCIDRs
+					discovery.PodCIDRsSource = genericNet.PodCIDRsSource
+					discovery.PodCIDRsConfidence = genericNet.PodCIDRsConfidence
 				}
 			}
 		}
@@ -93,6 +221,11 @@ func Discover(ctx context.Context, client controllerClient.Client, operatorNames
 	}
 
 	if discovery != nil {
+		// Plugins read their CIDRs from their configuration
+		if len(discovery.PodCIDRs) > 0 && discovery.PodCIDRsConfidence == "" {
+			discovery.PodCIDRsConfidence = ConfidenceHigh
+		}
+
 		globalCIDR, clustersetIPCIDR, _ := getCIDRs(ctx, client, operatorNamespace)
 		discovery.GlobalCIDR = globalCIDR
 		discovery.ClustersetIPCIDR = clustersetIPCIDR
@@ -106,7 +239,10 @@ type pluginDiscoveryFn func(context.Context, controllerClient.Client) (*ClusterN
 var discoverFunctions = []pluginDiscoveryFn{
 	discoverOpenShift4Network,
 	discoverOvnKubernetesNetwork,
+	discoverCiliumNetwork,
 	discoverWeaveNetwork,
+	discoverAntreaNetwork,
+	discoverKubeOVNNetwork,
 	discoverCanalFlannelNetwork,
 	discoverCalicoNetwork,
 	discoverFlannelNetwork,
@@ -137,8 +273,9 @@ func getCIDRs(ctx context.Context, operatorClient controllerClient.Client, opera
 		return "", "", errors.Wrap(err, "error retrieving Submariner resource")
 	}
 
-	globalCIDR := existingCfg.Spec.GlobalCIDR
-	clustersetIPCIDR := existingCfg.Spec.ClustersetIPCIDR
+	// CIDRs allocated from the broker are only recorded in the status.
+	globalCIDR := cmp.Or(existingCfg.Spec.GlobalCIDR, existingCfg.Status.GlobalCIDR)
+	clustersetIPCIDR := cmp.Or(existingCfg.Spec.ClustersetIPCIDR, existingCfg.Status.ClustersetIPCIDR)
 
 	return globalCIDR, clustersetIPCIDR, nil
 }
@@ -28,8 +28,10 @@ import (
 	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
 	v1 "k8s.io/api/core/v1"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -110,6 +112,28 @@ func fakeNode(name, podCIDR string) *v1.Node {
 	}
 }
 
+func fakeNodeWithPodCIDRs(name string, podCIDRs ...string) *v1.Node {
+	node := fakeNode(name, podCIDRs[0])
+	node.Spec.PodCIDRs = podCIDRs
+
+	return node
+}
+
+func fakeServiceCIDR(apiVersion, name string, deleted bool, cidrs ...string) *unstructured.Unstructured {
+	serviceCIDR := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	serviceCIDR.SetAPIVersion(apiVersion)
+	serviceCIDR.SetKind("ServiceCIDR")
+	serviceCIDR.SetName(name)
+	Expect(unstructured.SetNestedStringSlice(serviceCIDR.Object, cidrs, "spec", "cidrs")).To(Succeed())
+
+	if deleted {
+		serviceCIDR.SetFinalizers([]string{"networking.k8s.io/service-cidr-finalizer"})
+		serviceCIDR.SetDeletionTimestamp(ptr.To(v1meta.Now()))
+	}
+
+	return serviceCIDR
+}
+
 func testDiscoverNetworkSuccess(ctx context.Context, objects ...controllerClient.Object) *network.ClusterNetwork {
 	clusterNet, err := testDiscoverNetwork(ctx, objects...)
 	Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,131 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"math/bits"
+	"net"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// aggregateNodePodCIDRs infers the cluster's pod CIDRs from the ranges assigned to its nodes: for each IP family, the
+// smallest supernet covering the nodes' ranges. The result is only meaningful if the ranges were carved out of the
+// same cluster CIDR, so nothing is returned unless every node has one range of the same size per family, without
+// any overlap. Nodes which haven't been assigned a range yet are ignored; a single node's ranges are returned as-is.
+func aggregateNodePodCIDRs(nodes []corev1.Node) ([]string, Confidence) {
+	var (
+		families       []int
+		rangesByFamily = map[int][]*net.IPNet{}
+		nodeCount      int
+		firstNodeCIDRs []string
+	)
+
+	for i := range nodes {
+		nodeCIDRs := nodes[i].Spec.PodCIDRs
+		if len(nodeCIDRs) == 0 && nodes[i].Spec.PodCIDR != "" {
+			nodeCIDRs = []string{nodes[i].Spec.PodCIDR}
+		}
+
+		if len(nodeCIDRs) == 0 {
+			continue
+		}
+
+		nodeCount++
+
+		if firstNodeCIDRs == nil {
+			firstNodeCIDRs = nodeCIDRs
+		}
+
+		for _, nodeCIDR := range nodeCIDRs {
+			_, ipNet, err := net.ParseCIDR(nodeCIDR)
+			if err != nil {
+				return nil, ""
+			}
+
+			family := len(ipNet.IP)
+			if !slices.Contains(families, family) {
+				families = append(families, family)
+			}
+
+			rangesByFamily[family] = append(rangesByFamily[family], ipNet)
+		}
+	}
+
+	switch nodeCount {
+	case 0:
+		return nil, ""
+	case 1:
+		return firstNodeCIDRs, ConfidenceLow
+	}
+
+	podCIDRs := make([]string, 0, len(families))
+
+	for _, family := range families {
+		supernet := coveringSupernet(rangesByFamily[family], nodeCount)
+		if supernet == nil {
+			return nil, ""
+		}
+
+		podCIDRs = append(podCIDRs, supernet.String())
+	}
+
+	return podCIDRs, ConfidenceMedium
+}
+
+// coveringSupernet returns the smallest network covering the given ranges, which must be one per node, all of the
+// same size and distinct, or nil otherwise.
+func coveringSupernet(ranges []*net.IPNet, nodeCount int) *net.IPNet {
+	if len(ranges) != nodeCount {
+		return nil
+	}
+
+	rangeOnes, _ := ranges[0].Mask.Size()
+	prefixLength := rangeOnes
+
+	for i, ipNet := range ranges {
+		if ones, _ := ipNet.Mask.Size(); ones != rangeOnes {
+			return nil
+		}
+
+		// Ranges of the same size either match or don't overlap at all
+		for _, other := range ranges[:i] {
+			if ipNet.IP.Equal(other.IP) {
+				return nil
+			}
+		}
+
+		prefixLength = min(prefixLength, commonPrefixLength(ranges[0].IP, ipNet.IP))
+	}
+
+	mask := net.CIDRMask(prefixLength, len(ranges[0].IP)*8)
+
+	return &net.IPNet{IP: ranges[0].IP.Mask(mask), Mask: mask}
+}
+
+func commonPrefixLength(a, b net.IP) int {
+	for i := range a {
+		if diff := a[i] ^ b[i]; diff != 0 {
+			return i*8 + bits.LeadingZeros8(diff)
+		}
+	}
+
+	return len(a) * 8
+}
@@ -53,7 +53,11 @@ func discoverOpenShift4Network(ctx context.Context, client controllerClient.Clie
 }
 
 func parseOS4Network(cr *unstructured.Unstructured) (*ClusterNetwork, error) {
-	result := &ClusterNetwork{}
+	result := &ClusterNetwork{
+		PodCIDRsSource:        SourceOpenShiftNetwork,
+		ServiceCIDRsSource:    SourceOpenShiftNetwork,
+		NetworkPluginEvidence: evidence("Network.config.openshift.io", cr),
+	}
 
 	clusterNetworks, found, err := unstructured.NestedSlice(cr.Object, "spec", "clusterNetwork")
 	if err != nil {
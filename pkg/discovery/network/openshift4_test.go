@@ -40,6 +40,9 @@ var _ = Describe("OpenShift4 Network", func() {
 			Expect(cn.PodCIDRs).To(Equal([]string{"10.128.0.0/14", "10.132.0.0/14"}))
 			Expect(cn.ServiceCIDRs).To(Equal([]string{"172.30.0.0/16"}))
 			Expect(cn.NetworkPlugin).To(Equal(cni.OpenShiftSDN))
+			Expect(cn.NetworkPluginEvidence).To(Equal("Network.config.openshift.io cluster"))
+			Expect(cn.PodCIDRsSource).To(Equal(network.SourceOpenShiftNetwork))
+			Expect(cn.ServiceCIDRsSource).To(Equal(network.SourceOpenShiftNetwork))
 		})
 	})
 
@@ -33,7 +33,10 @@ func discoverOvnKubernetesNetwork(ctx context.Context, client controllerClient.C
 		return nil, err
 	}
 
-	clusterNetwork := &ClusterNetwork{NetworkPlugin: cni.OVNKubernetes}
+	clusterNetwork := &ClusterNetwork{
+		NetworkPlugin:         cni.OVNKubernetes,
+		NetworkPluginEvidence: evidence("Pod", ovnPod),
+	}
 
 	updateClusterNetworkFromConfigMap(ctx, client, ovnPod.Namespace, clusterNetwork)
 
@@ -49,10 +52,12 @@ func updateClusterNetworkFromConfigMap(ctx context.Context, client controllerCli
 	if err == nil {
 		if netCidr, ok := ovnConfig.Data["net_cidr"]; ok {
 			clusterNetwork.PodCIDRs = []string{netCidr}
+			clusterNetwork.PodCIDRsSource = SourcePluginConfig
 		}
 
 		if svcCidr, ok := ovnConfig.Data["svc_cidr"]; ok {
 			clusterNetwork.ServiceCIDRs = []string{svcCidr}
+			clusterNetwork.ServiceCIDRsSource = SourcePluginConfig
 		}
 	}
 }
@@ -0,0 +1,84 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	rke2NodeArgsAnnotation = "rke2.io/node-args"
+	rke2ChartPrefix        = "rke2-"
+	rke2ClusterCIDRValue   = "global.clusterCIDR"
+	rke2ServiceCIDRValue   = "global.serviceCIDR"
+)
+
+var helmChartGVK = schema.GroupVersionKind{Group: "helm.cattle.io", Version: "v1", Kind: "HelmChartList"}
+
+// findRKE2CIDRs returns the CIDRs in the RKE2 server configuration. RKE2 passes them to the charts it bundles, such as
+// rke2-coredns and its CNI charts, as global values; failing that, the server arguments recorded in the nodes'
+// annotations are used.
+func findRKE2CIDRs(ctx context.Context, client controllerClient.Client) (clusterConfigCIDRs, error) {
+	cidrs, err := findRKE2ChartCIDRs(ctx, client)
+	if err != nil || len(cidrs.podCIDRs) > 0 || len(cidrs.serviceCIDRs) > 0 {
+		return cidrs, err
+	}
+
+	return findNodeArgsCIDRs(ctx, client, rke2NodeArgsAnnotation)
+}
+
+func findRKE2ChartCIDRs(ctx context.Context, client controllerClient.Client) (clusterConfigCIDRs, error) {
+	charts := &unstructured.UnstructuredList{}
+	charts.SetGroupVersionKind(helmChartGVK)
+
+	err := client.List(ctx, charts, controllerClient.InNamespace(metav1.NamespaceSystem))
+	if resource.IsNotFoundErr(err) {
+		return clusterConfigCIDRs{}, nil
+	}
+
+	if err != nil {
+		return clusterConfigCIDRs{}, errors.Wrap(err, "error listing the RKE2 HelmCharts")
+	}
+
+	for i := range charts.Items {
+		if !strings.HasPrefix(charts.Items[i].GetName(), rke2ChartPrefix) {
+			continue
+		}
+
+		clusterCIDR, _, _ := unstructured.NestedString(charts.Items[i].Object, "spec", "set", rke2ClusterCIDRValue)
+		serviceCIDR, _, _ := unstructured.NestedString(charts.Items[i].Object, "spec", "set", rke2ServiceCIDRValue)
+
+		if clusterCIDR != "" || serviceCIDR != "" {
+			return clusterConfigCIDRs{
+				podCIDRs:     splitCIDRs(clusterCIDR),
+				serviceCIDRs: splitCIDRs(serviceCIDR),
+			}, nil
+		}
+	}
+
+	return clusterConfigCIDRs{}, nil
+}
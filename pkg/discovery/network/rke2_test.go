@@ -0,0 +1,93 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/network"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("RKE2 Network", func() {
+	When("the RKE2 charts were given the cluster's CIDRs", func() {
+		It("should return a ClusterNetwork with the CIDRs set correctly", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx,
+				newRKE2HelmChart("traefik", "10.72.0.0/16", "10.73.0.0/16"),
+				newRKE2HelmChart("rke2-coredns", "10.62.0.0/16,fd00:62::/56", "10.63.0.0/16"),
+				newNodeWithArgs("server", "rke2.io/node-args", `["server","--cluster-cidr","10.82.0.0/16"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.62.0.0/16", "fd00:62::/56"}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceRKE2Config))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.63.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceRKE2Config))
+		})
+	})
+
+	When("there are no RKE2 charts and an RKE2 server was configured with CIDRs", func() {
+		It("should return a ClusterNetwork with the CIDRs from the node arguments", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx,
+				newNodeWithArgs("server", "rke2.io/node-args",
+					`["server","--cluster-cidr","10.62.0.0/16","--service-cidr","10.63.0.0/16","--cni","canal"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.62.0.0/16"}))
+			Expect(clusterNet.PodCIDRsSource).To(Equal(network.SourceRKE2Config))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.63.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceRKE2Config))
+		})
+	})
+
+	When("there are no RKE2 charts and an RKE2 server was configured without CIDRs", func() {
+		It("should return a ClusterNetwork with the default RKE2 CIDRs", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newNodeWithArgs("server", "rke2.io/node-args", `["server","--cni","cilium"]`))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.PodCIDRs).To(Equal([]string{"10.42.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{"10.43.0.0/16"}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceRKE2Config))
+		})
+	})
+
+	When("the RKE2 node arguments can't be parsed", func() {
+		It("should fall back to the next source", func(ctx SpecContext) {
+			clusterNet := testDiscoverNetworkSuccess(ctx, newNodeWithArgs("server", "rke2.io/node-args", "server"))
+			Expect(clusterNet).NotTo(BeNil())
+			Expect(clusterNet.ServiceCIDRs).To(Equal([]string{testServiceCIDRFromService}))
+			Expect(clusterNet.ServiceCIDRsSource).To(Equal(network.SourceInvalidServiceError))
+		})
+	})
+})
+
+func newRKE2HelmChart(name, clusterCIDR, serviceCIDR string) *unstructured.Unstructured {
+	chart := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"set": map[string]interface{}{
+				"global.clusterCIDR": clusterCIDR,
+				"global.serviceCIDR": serviceCIDR,
+			},
+		},
+	}}
+
+	chart.SetAPIVersion("helm.cattle.io/v1")
+	chart.SetKind("HelmChart")
+	chart.SetName(name)
+	chart.SetNamespace(metav1.NamespaceSystem)
+
+	return chart
+}
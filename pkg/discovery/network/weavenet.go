@@ -33,21 +33,23 @@ func discoverWeaveNetwork(ctx context.Context, client controllerClient.Client) (
 	}
 
 	clusterNetwork := &ClusterNetwork{
-		NetworkPlugin: cni.WeaveNet,
+		NetworkPlugin:         cni.WeaveNet,
+		NetworkPluginEvidence: evidence("Pod", weaveNetPod),
 	}
 
 	for i := range weaveNetPod.Spec.Containers {
 		for _, envVar := range weaveNetPod.Spec.Containers[i].Env {
 			if envVar.Name == "IPALLOC_RANGE" {
 				clusterNetwork.PodCIDRs = []string{envVar.Value}
+				clusterNetwork.PodCIDRsSource = SourcePluginConfig
 				break
 			}
 		}
 	}
 
-	clusterIPRange, err := findClusterIPRange(ctx, client)
-	if err == nil && clusterIPRange != "" {
-		clusterNetwork.ServiceCIDRs = []string{clusterIPRange}
+	clusterIPRanges, err := findClusterIPRanges(ctx, client)
+	if err == nil {
+		clusterNetwork.setServiceCIDRs(clusterIPRanges)
 	}
 
 	return clusterNetwork, nil
@@ -34,6 +34,8 @@ var files = []string{
 	"deploy/crds/submariner.io_brokers.yaml",
 	"deploy/crds/submariner.io_submariners.yaml",
 	"deploy/crds/submariner.io_servicediscoveries.yaml",
+	"deploy/crds/submariner.io_clusterjoinrequests.yaml",
+	"deploy/crds/submariner.io_cidrallocations.yaml",
 	"deploy/submariner/crds/submariner.io_clusters.yaml",
 	"deploy/submariner/crds/submariner.io_endpoints.yaml",
 	"deploy/submariner/crds/submariner.io_gateways.yaml",
@@ -61,6 +61,11 @@ spec:
           spec:
             description: BrokerSpec defines the desired state of Broker.
             properties:
+              brokerK8sApiServer:
+                description: |-
+                  The broker API URL advertised to joining clusters. If not specified, the URL the operator uses to access
+                  the broker cluster is advertised.
+                type: string
               clustersetIPCIDRRange:
                 description: ClustersetIP supernet range for allocating ClustersetIPCIDRs
                   to each cluster.
@@ -69,8 +74,9 @@ spec:
                 description: Enable ClustersetIP default for connecting clusters.
                 type: boolean
               components:
-                description: List of the components to be installed - any of [service-discovery,
-                  connectivity].
+                description: |-
+                  List of the components to be installed - any of [service-discovery, connectivity]. All the components are installed
+                  if none are specified.
                 items:
                   type: string
                 type: array
@@ -90,9 +96,191 @@ spec:
               globalnetEnabled:
                 description: Enable support for Overlapping CIDRs in connecting clusters.
                 type: boolean
+              staleClusterGracePeriod:
+                description: |-
+                  The time a stale cluster remains marked as such before its broker resources are removed and its CIDRs are released.
+                  Defaults to the stale cluster timeout.
+                type: string
+              staleClusterTimeout:
+                description: |-
+                  The time after which a member cluster that has stopped renewing its heartbeat on the broker is considered stale.
+                  Stale clusters aren't detected if not specified. Clusters which have never sent a heartbeat are never considered stale.
+                type: string
             type: object
           status:
             description: BrokerStatus defines the observed state of Broker.
+            properties:
+              cidrConflicts:
+                description: The overlapping cluster and service CIDRs of member clusters
+                  which don't use globalnet.
+                items:
+                  description: CIDRConflict describes a cluster or service CIDR of
+                    a member cluster which overlaps one of another member cluster.
+                  properties:
+                    cidr:
+                      type: string
+                    clusterID:
+                      type: string
+                    otherCIDR:
+                      type: string
+                    otherClusterID:
+                      type: string
+                  required:
+                  - cidr
+                  - clusterID
+                  - otherCIDR
+                  - otherClusterID
+                  type: object
+                type: array
+              clustersetIPCIDRPool:
+                description: The utilization of the clustersetIP supernet, if clustersetIP
+                  is enabled.
+                properties:
+                  allocationSize:
+                    description: The default number of addresses allocated to each
+                      cluster.
+                    type: string
+                  availableAllocations:
+                    description: The number of further allocations of the default
+                      size which fit in the supernet.
+                    type: string
+                  cidr:
+                    description: The supernet.
+                    type: string
+                  fragmentationPercent:
+                    description: The percentage of unallocated addresses which can't
+                      be used by allocations of the default size.
+                    format: int32
+                    type: integer
+                  nextAllocation:
+                    description: The CIDR the next cluster would be allocated with
+                      the default size, if any.
+                    type: string
+                  totalAddresses:
+                    description: The number of addresses in the supernet.
+                    type: string
+                  usedAddresses:
+                    description: The number of addresses allocated to clusters.
+                    type: string
+                required:
+                - availableAllocations
+                - cidr
+                - fragmentationPercent
+                - totalAddresses
+                - usedAddresses
+                type: object
+              conditions:
+                items:
+                  description: Condition contains details for one aspect of the current
+                    state of this API Resource.
+                  properties:
+                    lastTransitionTime:
+                      description: |-
+                        lastTransitionTime is the last time the condition transitioned from one status to another.
+                        This should be when the underlying condition changed.  If that is not known, then using the time when the API field changed is acceptable.
+                      format: date-time
+                      type: string
+                    message:
+                      description: |-
+                        message is a human readable message indicating details about the transition.
+                        This may be an empty string.
+                      maxLength: 32768
+                      type: string
+                    observedGeneration:
+                      description: |-
+                        observedGeneration represents the .metadata.generation that the condition was set based upon.
+                        For instance, if .metadata.generation is currently 12, but the .status.conditions[x].observedGeneration is 9, the condition is out of date
+                        with respect to the current state of the instance.
+                      format: int64
+                      minimum: 0
+                      type: integer
+                    reason:
+                      description: |-
+                        reason contains a programmatic identifier indicating the reason for the condition's last transition.
+                        Producers of specific condition types may define expected values and meanings for this field,
+                        and whether the values are considered a guaranteed API.
+                        The value should be a CamelCase string.
+                        This field may not be empty.
+                      maxLength: 1024
+                      minLength: 1
+                      pattern: ^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$
+                      type: string
+                    status:
+                      description: status of the condition, one of True, False, Unknown.
+                      enum:
+                      - "True"
+                      - "False"
+                      - Unknown
+                      type: string
+                    type:
+                      description: type of condition in CamelCase or in foo.example.com/CamelCase.
+                      maxLength: 316
+                      pattern: ^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$
+                      type: string
+                  required:
+                  - lastTransitionTime
+                  - message
+                  - reason
+                  - status
+                  - type
+                  type: object
+                type: array
+              globalnetCIDRPool:
+                description: The utilization of the globalnet supernet, if globalnet
+                  is enabled.
+                properties:
+                  allocationSize:
+                    description: The default number of addresses allocated to each
+                      cluster.
+                    type: string
+                  availableAllocations:
+                    description: The number of further allocations of the default
+                      size which fit in the supernet.
+                    type: string
+                  cidr:
+                    description: The supernet.
+                    type: string
+                  fragmentationPercent:
+                    description: The percentage of unallocated addresses which can't
+                      be used by allocations of the default size.
+                    format: int32
+                    type: integer
+                  nextAllocation:
+                    description: The CIDR the next cluster would be allocated with
+                      the default size, if any.
+                    type: string
+                  totalAddresses:
+                    description: The number of addresses in the supernet.
+                    type: string
+                  usedAddresses:
+                    description: The number of addresses allocated to clusters.
+                    type: string
+                required:
+                - availableAllocations
+                - cidr
+                - fragmentationPercent
+                - totalAddresses
+                - usedAddresses
+                type: object
+              staleClusters:
+                description: Member clusters which have stopped renewing their heartbeat
+                  and whose broker resources are pending removal.
+                items:
+                  properties:
+                    clusterID:
+                      type: string
+                    lastHeartbeat:
+                      description: The last time the cluster renewed its heartbeat.
+                      format: date-time
+                      type: string
+                    staleSince:
+                      description: The time at which the cluster was found to be stale.
+                      format: date-time
+                      type: string
+                  required:
+                  - clusterID
+                  type: object
+                type: array
             type: object
         type: object
     served: true
@@ -141,8 +329,19 @@ spec:
           spec:
             description: SubmarinerSpec defines the desired state of Submariner.
             properties:
+              additionalGlobalCIDRs:
+                description: Further global CIDR blocks allocated to the cluster when
+                  it needs more global IPs than GlobalCIDR provides.
+                items:
+                  type: string
+                type: array
               airGappedDeployment:
                 type: boolean
+              applyNetworkChanges:
+                description: |-
+                  Apply changes found by network re-discovery, rolling the route agent and other components with the new network
+                  details. Otherwise, they're only reported by the NetworkDrift condition and events.
+                type: boolean
               broker:
                 description: Type of broker (must be "k8s").
                 type: string
@@ -155,6 +354,23 @@ spec:
               brokerK8sCA:
                 description: The broker certificate authority.
                 type: string
+              brokerK8sEndpoints:
+                description: |-
+                  Additional broker API endpoints, in order of preference, used as fallbacks if BrokerK8sApiServer becomes
+                  unavailable. The first available endpoint is used, starting with BrokerK8sApiServer.
+                items:
+                  properties:
+                    apiServer:
+                      description: The broker API URL.
+                      type: string
+                    ca:
+                      description: The certificate authority for this endpoint. If
+                        not specified, BrokerK8sCA is used.
+                      type: string
+                  required:
+                  - apiServer
+                  type: object
+                type: array
               brokerK8sInsecure:
                 type: boolean
               brokerK8sRemoteNamespace:
@@ -191,12 +407,20 @@ spec:
               clusterCIDR:
                 description: The cluster CIDR.
                 type: string
+              clusterCIDRs:
+                description: |-
+                  All the cluster CIDRs, for clusters with several pod networks such as dual-stack clusters. When specified, they
+                  take precedence over ClusterCIDR.
+                items:
+                  type: string
+                type: array
               clusterID:
                 description: The cluster ID used to identify the tunnels.
                 type: string
               clustersetIPCIDR:
-                description: ClustersetIP CIDR for allocating ClustersetIPs to exported
-                  services.
+                description: |-
+                  ClustersetIP CIDR for allocating ClustersetIPs to exported services.
+                  If empty and ClustersetIPEnabled is set, a CIDR is allocated to the cluster from the broker's pool and recorded in the status.
                 type: string
               clustersetIPEnabled:
                 description: Enable ClustersetIP default for services exported on
@@ -242,9 +466,15 @@ spec:
                 description: Enable operator debugging.
                 type: boolean
               globalCIDR:
-                description: The Global CIDR super-net range for allocating GlobalCIDRs
-                  to each cluster.
+                description: |-
+                  The Global CIDR super-net range for allocating GlobalCIDRs to each cluster.
+                  If empty and globalnet is enabled on the broker, a CIDR is allocated to the cluster from the broker's pool and recorded in the status.
                 type: string
+              globalnetClusterSize:
+                description: |-
+                  The number of global IPs to request in the cluster's global CIDR when it is allocated from the broker, overriding the
+                  broker's default cluster size. The broker rounds it up to a power of two. It is ignored if GlobalCIDR is specified.
+                type: integer
               haltOnCertificateError:
                 description: Halt on certificate error (so the pod gets restarted).
                 type: boolean
@@ -262,6 +492,13 @@ spec:
               natEnabled:
                 description: Enable NAT between clusters.
                 type: boolean
+              networkDiscoveryIntervalSeconds:
+                description: |-
+                  The interval in seconds at which the cluster network is re-discovered, to notice changes such as CNI migrations or
+                  added service CIDRs. Intervals shorter than a minute are rounded up to a minute. Zero disables periodic
+                  re-discovery; changes to the network plugin's configuration still trigger it.
+                format: int64
+                type: integer
               nodeSelector:
                 additionalProperties:
                   type: string
@@ -272,6 +509,13 @@ spec:
               serviceCIDR:
                 description: The service CIDR.
                 type: string
+              serviceCIDRs:
+                description: |-
+                  All the service CIDRs, for clusters with several service networks such as dual-stack clusters. When specified,
+                  they take precedence over ServiceCIDR.
+                items:
+                  type: string
+                type: array
               serviceDiscoveryEnabled:
                 description: Enable support for Service Discovery (Lighthouse).
                 type: boolean
@@ -331,19 +575,114 @@ spec:
           status:
             description: SubmarinerStatus defines the observed state of Submariner.
             properties:
+              additionalGlobalCIDRs:
+                description: The current further global CIDR blocks, as specified
+                  or allocated from the broker.
+                items:
+                  type: string
+                type: array
+              activeBrokerK8sApiServer:
+                description: The broker API server currently in use.
+                type: string
               airGappedDeployment:
                 type: boolean
+              brokerSecretSyncer:
+                description: The status of the syncer maintaining the local copy of
+                  the broker secret.
+                properties:
+                  brokerK8sApiServer:
+                    description: The broker API server the syncer is connected to.
+                    type: string
+                  brokerK8sRemoteNamespace:
+                    description: The broker namespace the syncer watches.
+                    type: string
+                  lastError:
+                    description: The error returned by the last sync attempt, if it
+                      failed.
+                    type: string
+                  lastSyncTime:
+                    description: The time of the last successful sync of the broker
+                      secret.
+                    format: date-time
+                    type: string
+                  startTime:
+                    description: The time at which the syncer was started.
+                    format: date-time
+                    type: string
+                type: object
               clusterCIDR:
-                description: The current cluster CIDR.
+                description: The current cluster CIDR; the first of ClusterCIDRs.
                 type: string
+              clusterCIDRs:
+                description: All the current cluster CIDRs.
+                items:
+                  type: string
+                type: array
               clusterID:
                 description: The current cluster ID.
                 type: string
               clustersetIPCIDR:
-                description: The current clustersetIP CIDR.
+                description: The current clustersetIP CIDR, as specified or allocated
+                  from the broker.
                 type: string
               colorCodes:
                 type: string
+              conditions:
+                items:
+                  description: Condition contains details for one aspect of the current
+                    state of this API Resource.
+                  properties:
+                    lastTransitionTime:
+                      description: |-
+                        lastTransitionTime is the last time the condition transitioned from one status to another.
+                        This should be when the underlying condition changed.  If that is not known, then using the time when the API field changed is acceptable.
+                      format: date-time
+                      type: string
+                    message:
+                      description: |-
+                        message is a human readable message indicating details about the transition.
+                        This may be an empty string.
+                      maxLength: 32768
+                      type: string
+                    observedGeneration:
+                      description: |-
+                        observedGeneration represents the .metadata.generation that the condition was set based upon.
+                        For instance, if .metadata.generation is currently 12, but the .status.conditions[x].observedGeneration is 9, the condition is out of date
+                        with respect to the current state of the instance.
+                      format: int64
+                      minimum: 0
+                      type: integer
+                    reason:
+                      description: |-
+                        reason contains a programmatic identifier indicating the reason for the condition's last transition.
+                        Producers of specific condition types may define expected values and meanings for this field,
+                        and whether the values are considered a guaranteed API.
+                        The value should be a CamelCase string.
+                        This field may not be empty.
+                      maxLength: 1024
+                      minLength: 1
+                      pattern: ^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$
+                      type: string
+                    status:
+                      description: status of the condition, one of True, False, Unknown.
+                      enum:
+                      - "True"
+                      - "False"
+                      - Unknown
+                      type: string
+                    type:
+                      description: type of condition in CamelCase or in foo.example.com/CamelCase.
+                      maxLength: 316
+                      pattern: ^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$
+                      type: string
+                  required:
+                  - lastTransitionTime
+                  - message
+                  - reason
+                  - status
+                  - type
+                  type: object
+                type: array
               deploymentInfo:
                 description: Information about the deployment.
                 properties:
@@ -661,8 +1000,14 @@ spec:
                   type: object
                 type: array
               globalCIDR:
-                description: The current global CIDR.
+                description: The current global CIDR, as specified or allocated from
+                  the broker.
                 type: string
+              globalnetClusterSize:
+                description: The number of global IPs in the current global CIDR,
+                  as granted by the broker. It isn't reported while the allocation
+                  fails.
+                type: integer
               globalnetDaemonSetStatus:
                 description: The status of the Globalnet DaemonSet.
                 properties:
@@ -916,6 +1261,31 @@ spec:
               natEnabled:
                 description: The current NAT status.
                 type: boolean
+              networkDiscovery:
+                description: Where the current network details came from.
+                properties:
+                  clusterCIDRsConfidence:
+                    description: 'How reliable the discovered cluster CIDRs are: High,
+                      Medium or Low.'
+                    type: string
+                  clusterCIDRsSource:
+                    description: The source of the cluster CIDRs, such as KubeControllerManagerFlag
+                      or NodeSpec; Spec if they're configured.
+                    type: string
+                  networkPluginEvidence:
+                    description: The resource the network plugin was detected from.
+                    type: string
+                  pluginSettings:
+                    additionalProperties:
+                      type: string
+                    description: The network plugin's settings which affect the
+                      route agent, such as the encapsulation used by Calico's IPPools.
+                    type: object
+                  serviceCIDRsSource:
+                    description: The source of the service CIDRs, such as ServiceCIDR
+                      or KubeAPIServerFlag; Spec if they're configured.
+                    type: string
+                type: object
               networkPlugin:
                 description: The current network plugin.
                 type: string
@@ -1097,15 +1467,338 @@ spec:
                 - mismatchedContainerImages
                 type: object
               serviceCIDR:
-                description: The current service CIDR.
+                description: The current service CIDR; the first of ServiceCIDRs.
                 type: string
+              serviceCIDRs:
+                description: All the current service CIDRs.
+                items:
+                  type: string
+                type: array
               version:
                 description: The image version in use by the various Submariner DaemonSets
                   and Deployments.
                 type: string
-            required:
-            - clusterID
-            - natEnabled
+            required:
+            - clusterID
+            - natEnabled
+            type: object
+        type: object
+    served: true
+    storage: true
+    subresources:
+      status: {}
+`
+	Deploy_crds_submariner_io_servicediscoveries_yaml = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.16.1
+  name: servicediscoveries.submariner.io
+spec:
+  group: submariner.io
+  names:
+    kind: ServiceDiscovery
+    listKind: ServiceDiscoveryList
+    plural: servicediscoveries
+    singular: servicediscovery
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        description: ServiceDiscovery is the Schema for the servicediscoveries API.
+        properties:
+          apiVersion:
+            description: |-
+              APIVersion defines the versioned schema of this representation of an object.
+              Servers should convert recognized schemas to the latest internal value, and
+              may reject unrecognized values.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+            type: string
+          kind:
+            description: |-
+              Kind is a string value representing the REST resource this object represents.
+              Servers may infer this from the endpoint the client submits requests to.
+              Cannot be updated.
+              In CamelCase.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: ServiceDiscoverySpec defines the desired state of ServiceDiscovery.
+            properties:
+              brokerK8sApiServer:
+                type: string
+              brokerK8sApiServerToken:
+                type: string
+              brokerK8sCA:
+                type: string
+              brokerK8sInsecure:
+                type: boolean
+              brokerK8sRemoteNamespace:
+                type: string
+              brokerK8sSecret:
+                type: string
+              clusterID:
+                type: string
+              clustersetIPCIDR:
+                type: string
+              clustersetIPEnabled:
+                type: boolean
+              coreDNSCustomConfig:
+                properties:
+                  configMapName:
+                    description: Name of the custom CoreDNS configmap.
+                    type: string
+                  namespace:
+                    description: Namespace of the custom CoreDNS configmap.
+                    type: string
+                type: object
+              customDomains:
+                items:
+                  type: string
+                type: array
+                x-kubernetes-list-type: set
+              debug:
+                type: boolean
+              globalnetEnabled:
+                type: boolean
+              haltOnCertificateError:
+                type: boolean
+              imageOverrides:
+                additionalProperties:
+                  type: string
+                type: object
+              namespace:
+                type: string
+              nodeSelector:
+                additionalProperties:
+                  type: string
+                type: object
+              repository:
+                type: string
+              tolerations:
+                items:
+                  description: |-
+                    The pod this Toleration is attached to tolerates any taint that matches
+                    the triple <key,value,effect> using the matching operator <operator>.
+                  properties:
+                    effect:
+                      description: |-
+                        Effect indicates the taint effect to match. Empty means match all taint effects.
+                        When specified, allowed values are NoSchedule, PreferNoSchedule and NoExecute.
+                      type: string
+                    key:
+                      description: |-
+                        Key is the taint key that the toleration applies to. Empty means match all taint keys.
+                        If the key is empty, operator must be Exists; this combination means to match all values and all keys.
+                      type: string
+                    operator:
+                      description: |-
+                        Operator represents a key's relationship to the value.
+                        Valid operators are Exists and Equal. Defaults to Equal.
+                        Exists is equivalent to wildcard for value, so that a pod can
+                        tolerate all taints of a particular category.
+                      type: string
+                    tolerationSeconds:
+                      description: |-
+                        TolerationSeconds represents the period of time the toleration (which must be
+                        of effect NoExecute, otherwise this field is ignored) tolerates the taint. By default,
+                        it is not set, which means tolerate the taint forever (do not evict). Zero and
+                        negative values will be treated as 0 (evict immediately) by the system.
+                      format: int64
+                      type: integer
+                    value:
+                      description: |-
+                        Value is the taint value the toleration matches to.
+                        If the operator is Exists, the value should be empty, otherwise just a regular string.
+                      type: string
+                  type: object
+                type: array
+              version:
+                type: string
+            required:
+            - brokerK8sApiServer
+            - brokerK8sRemoteNamespace
+            - clusterID
+            - debug
+            - namespace
+            type: object
+          status:
+            description: ServiceDiscoveryStatus defines the observed state of ServiceDiscovery.
+            properties:
+              deploymentInfo:
+                properties:
+                  cloudProvider:
+                    type: string
+                  kubernetesType:
+                    type: string
+                  kubernetesTypeVersion:
+                    type: string
+                  kubernetesVersion:
+                    type: string
+                type: object
+            type: object
+        type: object
+    served: true
+    storage: true
+    subresources:
+      status: {}
+`
+	Deploy_crds_submariner_io_clusterjoinrequests_yaml = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.16.1
+  name: clusterjoinrequests.submariner.io
+spec:
+  group: submariner.io
+  names:
+    kind: ClusterJoinRequest
+    listKind: ClusterJoinRequestList
+    plural: clusterjoinrequests
+    singular: clusterjoinrequest
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        description: ClusterJoinRequest is the Schema for the clusterjoinrequests
+          API.
+        properties:
+          apiVersion:
+            description: |-
+              APIVersion defines the versioned schema of this representation of an object.
+              Servers should convert recognized schemas to the latest internal value, and
+              may reject unrecognized values.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+            type: string
+          kind:
+            description: |-
+              Kind is a string value representing the REST resource this object represents.
+              Servers may infer this from the endpoint the client submits requests to.
+              Cannot be updated.
+              In CamelCase.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: ClusterJoinRequestSpec defines the desired state of ClusterJoinRequest.
+            properties:
+              clusterID:
+                description: |-
+                  The ID of the cluster joining the cluster set. It can't be changed, since the cluster is granted access to its own
+                  requests.
+                type: string
+                x-kubernetes-validations:
+                - message: clusterID is immutable
+                  rule: self == oldSelf
+              clustersetIPCIDR:
+                description: |-
+                  The ClustersetIP CIDR to assign to the cluster. If not specified, one is allocated from the Broker's ClustersetIP
+                  CIDR range.
+                type: string
+              globalCIDR:
+                description: |-
+                  The global CIDR to assign to the cluster, if Globalnet is enabled. If not specified, one is allocated from the Broker's
+                  Globalnet CIDR range.
+                type: string
+              globalCIDRBlocks:
+                description: |-
+                  The number of global CIDR blocks to allocate to the cluster, 1 by default. It can be increased once the cluster has
+                  joined to give it more global IPs; blocks which are already allocated are never taken back.
+                minimum: 1
+                type: integer
+              globalnetClusterSize:
+                description: |-
+                  The number of global IPs in each of the cluster's global CIDR blocks, overriding the Broker's default cluster size.
+                  It can't be combined with GlobalCIDR.
+                type: integer
+            required:
+            - clusterID
+            type: object
+          status:
+            description: ClusterJoinRequestStatus defines the observed state of ClusterJoinRequest.
+            properties:
+              additionalGlobalCIDRs:
+                description: The further global CIDR blocks allocated to the cluster,
+                  as requested by GlobalCIDRBlocks.
+                items:
+                  type: string
+                type: array
+              clustersetIPCIDR:
+                description: The ClustersetIP CIDR allocated to the cluster.
+                type: string
+              conditions:
+                items:
+                  description: Condition contains details for one aspect of the current
+                    state of this API Resource.
+                  properties:
+                    lastTransitionTime:
+                      description: |-
+                        lastTransitionTime is the last time the condition transitioned from one status to another.
+                        This should be when the underlying condition changed.  If that is not known, then using the time when the API field changed is acceptable.
+                      format: date-time
+                      type: string
+                    message:
+                      description: |-
+                        message is a human readable message indicating details about the transition.
+                        This may be an empty string.
+                      maxLength: 32768
+                      type: string
+                    observedGeneration:
+                      description: |-
+                        observedGeneration represents the .metadata.generation that the condition was set based upon.
+                        For instance, if .metadata.generation is currently 12, but the .status.conditions[x].observedGeneration is 9, the condition is out of date
+                        with respect to the current state of the instance.
+                      format: int64
+                      minimum: 0
+                      type: integer
+                    reason:
+                      description: |-
+                        reason contains a programmatic identifier indicating the reason for the condition's last transition.
+                        Producers of specific condition types may define expected values and meanings for this field,
+                        and whether the values are considered a guaranteed API.
+                        The value should be a CamelCase string.
+                        This field may not be empty.
+                      maxLength: 1024
+                      minLength: 1
+                      pattern: ^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$
+                      type: string
+                    status:
+                      description: status of the condition, one of True, False, Unknown.
+                      enum:
+                      - "True"
+                      - "False"
+                      - Unknown
+                      type: string
+                    type:
+                      description: type of condition in CamelCase or in foo.example.com/CamelCase.
+                      maxLength: 316
+                      pattern: ^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$
+                      type: string
+                  required:
+                  - lastTransitionTime
+                  - message
+                  - reason
+                  - status
+                  - type
+                  type: object
+                type: array
+              globalCIDR:
+                description: The global CIDR allocated to the cluster.
+                type: string
+              joinBundleSecret:
+                description: The Secret holding the settings the cluster needs to
+                  join, once they are all available.
+                type: string
+              serviceAccount:
+                description: The broker ServiceAccount created for the cluster.
+                type: string
             type: object
         type: object
     served: true
@@ -1113,26 +1806,40 @@ spec:
     subresources:
       status: {}
 `
-	Deploy_crds_submariner_io_servicediscoveries_yaml = `---
+	Deploy_crds_submariner_io_cidrallocations_yaml = `---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
 metadata:
   annotations:
     controller-gen.kubebuilder.io/version: v0.16.1
-  name: servicediscoveries.submariner.io
+  name: cidrallocations.submariner.io
 spec:
   group: submariner.io
   names:
-    kind: ServiceDiscovery
-    listKind: ServiceDiscoveryList
-    plural: servicediscoveries
-    singular: servicediscovery
+    kind: CIDRAllocation
+    listKind: CIDRAllocationList
+    plural: cidrallocations
+    singular: cidrallocation
   scope: Namespaced
   versions:
-  - name: v1alpha1
+  - additionalPrinterColumns:
+    - jsonPath: .spec.clusterID
+      name: Cluster
+      type: string
+    - jsonPath: .spec.pool
+      name: Pool
+      type: string
+    - jsonPath: .spec.cidrs
+      name: CIDRs
+      type: string
+    - jsonPath: .metadata.creationTimestamp
+      name: Age
+      type: date
+    name: v1alpha1
     schema:
       openAPIV3Schema:
-        description: ServiceDiscovery is the Schema for the servicediscoveries API.
+        description: CIDRAllocation records the CIDRs allocated to a cluster from
+          one of the broker's pools.
         properties:
           apiVersion:
             description: |-
@@ -1152,125 +1859,33 @@ spec:
           metadata:
             type: object
           spec:
-            description: ServiceDiscoverySpec defines the desired state of ServiceDiscovery.
+            description: CIDRAllocationSpec defines the CIDRs allocated to a cluster
+              from one of the broker's pools.
             properties:
-              brokerK8sApiServer:
-                type: string
-              brokerK8sApiServerToken:
-                type: string
-              brokerK8sCA:
-                type: string
-              brokerK8sInsecure:
-                type: boolean
-              brokerK8sRemoteNamespace:
-                type: string
-              brokerK8sSecret:
-                type: string
-              clusterID:
-                type: string
-              clustersetIPCIDR:
-                type: string
-              clustersetIPEnabled:
-                type: boolean
-              coreDNSCustomConfig:
-                properties:
-                  configMapName:
-                    description: Name of the custom CoreDNS configmap.
-                    type: string
-                  namespace:
-                    description: Namespace of the custom CoreDNS configmap.
-                    type: string
-                type: object
-              customDomains:
+              cidrs:
+                description: The CIDRs allocated to the cluster.
                 items:
                   type: string
                 type: array
-                x-kubernetes-list-type: set
-              debug:
-                type: boolean
-              globalnetEnabled:
-                type: boolean
-              haltOnCertificateError:
-                type: boolean
-              imageOverrides:
-                additionalProperties:
-                  type: string
-                type: object
-              namespace:
-                type: string
-              nodeSelector:
-                additionalProperties:
-                  type: string
-                type: object
-              repository:
+              clusterID:
+                description: The ID of the cluster the CIDRs are allocated to.
                 type: string
-              tolerations:
-                items:
-                  description: |-
-                    The pod this Toleration is attached to tolerates any taint that matches
-                    the triple <key,value,effect> using the matching operator <operator>.
-                  properties:
-                    effect:
-                      description: |-
-                        Effect indicates the taint effect to match. Empty means match all taint effects.
-                        When specified, allowed values are NoSchedule, PreferNoSchedule and NoExecute.
-                      type: string
-                    key:
-                      description: |-
-                        Key is the taint key that the toleration applies to. Empty means match all taint keys.
-                        If the key is empty, operator must be Exists; this combination means to match all values and all keys.
-                      type: string
-                    operator:
-                      description: |-
-                        Operator represents a key's relationship to the value.
-                        Valid operators are Exists and Equal. Defaults to Equal.
-                        Exists is equivalent to wildcard for value, so that a pod can
-                        tolerate all taints of a particular category.
-                      type: string
-                    tolerationSeconds:
-                      description: |-
-                        TolerationSeconds represents the period of time the toleration (which must be
-                        of effect NoExecute, otherwise this field is ignored) tolerates the taint. By default,
-                        it is not set, which means tolerate the taint forever (do not evict). Zero and
-                        negative values will be treated as 0 (evict immediately) by the system.
-                      format: int64
-                      type: integer
-                    value:
-                      description: |-
-                        Value is the taint value the toleration matches to.
-                        If the operator is Exists, the value should be empty, otherwise just a regular string.
-                      type: string
-                  type: object
-                type: array
-              version:
+              pool:
+                description: The pool the CIDRs are allocated from - either globalnet
+                  or clustersetip.
+                enum:
+                - globalnet
+                - clustersetip
                 type: string
             required:
-            - brokerK8sApiServer
-            - brokerK8sRemoteNamespace
+            - cidrs
             - clusterID
-            - debug
-            - namespace
-            type: object
-          status:
-            description: ServiceDiscoveryStatus defines the observed state of ServiceDiscovery.
-            properties:
-              deploymentInfo:
-                properties:
-                  cloudProvider:
-                    type: string
-                  kubernetesType:
-                    type: string
-                  kubernetesTypeVersion:
-                    type: string
-                  kubernetesVersion:
-                    type: string
-                type: object
+            - pool
             type: object
         type: object
     served: true
     storage: true
-    subresources:
-      status: {}
+    subresources: {}
 `
 	Deploy_submariner_crds_submariner_io_clusters_yaml = `---
 apiVersion: apiextensions.k8s.io/v1
@@ -2549,6 +3164,17 @@ rules:
       - patch
       - update
       - delete
+  - apiGroups:
+      - submariner.io
+    resources:
+      - cidrallocations
+    verbs:
+      - create
+      - get
+      - list
+      - watch
+      - update
+      - delete
   - apiGroups:
       - submariner.io
     resources:
@@ -2650,6 +3276,25 @@ rules:
     verbs:
       - get
       - list
+  - apiGroups:
+      - submariner.io
+    resources:
+      # CIDRs are allocated by the broker, as requested in each member cluster's ClusterJoinRequests; each member
+      # cluster may only update its own requests and release its own allocations, see the cluster's member Role.
+      - clusterjoinrequests
+      - cidrallocations
+    verbs:
+      - get
+      - list
+  - apiGroups:
+      - ""
+    resources:
+      - configmaps
+    resourceNames:
+      - submariner-globalnet-info
+      - submariner-clustersetip-info
+    verbs:
+      - get
   - apiGroups:
       - multicluster.x-k8s.io
     resources:
@@ -2732,13 +3377,69 @@ rules:
   - apiGroups:
       - ""
     resources:
-      # For syncing Secrets from the broker
+      # For network drift events on the Submariner resource
+      - events
+    verbs:
+      - create
+      - patch
+  - apiGroups:
+      - ""
+    resources:
+      # For syncing Secrets from the broker, and for cluster join requests on the broker
       - secrets
     verbs:
       - get
+      - list
+      - watch
+      - create
+      - update
+      - delete
+  - apiGroups:
+      - ""
+    resources:
+      # For the broker RBAC, cluster join requests and stale member clusters on the broker
+      - serviceaccounts
+    verbs:
+      - get
+      - list
+      - watch
+      - create
+      - update
+      - delete
+  - apiGroups:
+      - rbac.authorization.k8s.io
+    resources:
+      # For the broker RBAC, cluster join requests and stale member clusters on the broker
+      - rolebindings
+    verbs:
+      - get
+      - list
+      - watch
+      - create
+      - update
+      - delete
+  - apiGroups:
+      - rbac.authorization.k8s.io
+    resources:
+      # For the broker RBAC, member cluster Roles and stale member clusters on the broker
+      - roles
+    verbs:
+      - get
+      - list
+      - watch
       - create
       - update
       - delete
+  - apiGroups:
+      - rbac.authorization.k8s.io
+    resources:
+      - roles
+    resourceNames:
+      - submariner-k8s-broker-admin
+      - submariner-k8s-broker-cluster
+    verbs:
+      - bind
+      - escalate
   - apiGroups:
       - ""
     resources:
@@ -2786,6 +3487,20 @@ rules:
       - submariners/status
       - servicediscoveries
       - servicediscoveries/status
+      - clusterjoinrequests
+      - clusterjoinrequests/status
+    verbs:
+      - get
+      - list
+      - watch
+      - create
+      - update
+      - delete
+  - apiGroups:
+      - submariner.io
+    resources:
+      # CIDR allocations on the broker
+      - cidrallocations
     verbs:
       - get
       - list
@@ -2793,6 +3508,7 @@ rules:
       - create
       - update
       - delete
+      - deletecollection
   - apiGroups:
       - submariner.io
     resources:
@@ -2801,6 +3517,29 @@ rules:
       - get
       - list
       - watch
+  - apiGroups:
+      - submariner.io
+    resources:
+      # For removing stale member clusters from the broker
+      - clusters
+      - endpoints
+    verbs:
+      - get
+      - list
+      - watch
+      - delete
+  - apiGroups:
+      - coordination.k8s.io
+    resources:
+      # Member cluster heartbeats on the broker
+      - leases
+    verbs:
+      - get
+      - list
+      - watch
+      - create
+      - update
+      - delete
   - apiGroups:
       - submariner.io
     resources:
@@ -2841,6 +3580,8 @@ rules:
       - list
       - watch
       - update
+      # Broker ConfigMaps of removed components
+      - delete
   - apiGroups:
       - apiextensions.k8s.io
     resources:
@@ -2879,6 +3620,50 @@ rules:
       - cluster
     verbs:
       - get
+  - apiGroups:
+      - config.openshift.io
+    resources:
+      # Needed to re-discover the network settings when they change
+      - networks
+    verbs:
+      - list
+      - watch
+  - apiGroups:
+      - networking.k8s.io
+    resources:
+      # Needed for service CIDR discovery
+      - servicecidrs
+    verbs:
+      - list
+  - apiGroups:
+      - helm.cattle.io
+    resources:
+      # Needed for RKE2 CIDR discovery
+      - helmcharts
+    verbs:
+      - list
+  - apiGroups:
+      - crd.projectcalico.org
+      - projectcalico.org
+    resources:
+      # Needed for Calico CNI discovery
+      - ippools
+    verbs:
+      - list
+  - apiGroups:
+      - cilium.io
+    resources:
+      # Needed for Cilium CNI discovery
+      - ciliumnodes
+    verbs:
+      - list
+  - apiGroups:
+      - kubeovn.io
+    resources:
+      # Needed for Kube-OVN CNI discovery
+      - subnets
+    verbs:
+      - list
   - apiGroups:
       - monitoring.coreos.com
     resources:
@@ -18,7 +18,10 @@ limitations under the License.
 
 package names
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 /* CR names and other constants. */
 const (
@@ -47,3 +50,29 @@ func AppendUninstall(name string) string {
 func ForClusterSA(clusterID string) string {
 	return fmt.Sprintf("cluster-%s", clusterID)
 }
+
+// ClusterIDForSA returns the ID of the cluster whose broker ServiceAccount has the given name, if it is one.
+func ClusterIDForSA(saName string) (string, bool) {
+	clusterID, found := strings.CutPrefix(saName, ForClusterSA(""))
+	return clusterID, found && clusterID != ""
+}
+
+func ForClusterJoinBundle(clusterID string) string {
+	return ForClusterSA(clusterID) + "-join-bundle"
+}
+
+func ForClusterHeartbeat(clusterID string) string {
+	return ForClusterSA(clusterID) + "-heartbeat"
+}
+
+func ForClusterRole(clusterID string) string {
+	return ForClusterSA(clusterID) + "-member"
+}
+
+func ForClusterJoinRequest(clusterID string) string {
+	return ForClusterSA(clusterID) + "-join"
+}
+
+func ForCIDRAllocation(pool, clusterID string) string {
+	return fmt.Sprintf("%s-%s", pool, clusterID)
+}